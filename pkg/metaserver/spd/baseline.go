@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubewharf/katalyst-core/pkg/util"
+)
+
+// podOwnerLister is an optional capability an SPDFetcher may implement to let BaselineResolver
+// list the actual running pods belonging to a given controller owner, via the fetcher's pod
+// informer, instead of relying on the owner's (possibly stale) pod template.
+type podOwnerLister interface {
+	ListPodsForOwner(ctx context.Context, namespace string, ownerUID types.UID) ([]*v1.Pod, error)
+}
+
+// baselineResolver computes baseline membership from the live set of pods belonging to a pod's
+// controller owner, so that admission-controller mutations and rolling updates cannot skew which
+// pods are considered baseline. When the pod's owner cannot be resolved, or the SPDFetcher does
+// not support listing pods by owner, it falls back to the existing sentinel-hash based path.
+type baselineResolver struct {
+	lister podOwnerLister
+}
+
+func newBaselineResolver(fetcher SPDFetcher) *baselineResolver {
+	lister, _ := fetcher.(podOwnerLister)
+	return &baselineResolver{lister: lister}
+}
+
+// isBaseline returns whether pod is baseline for baselinePercent, preferring the live-pod-set
+// resolution and falling back to util.IsBaselinePod's sentinel hash.
+func (r *baselineResolver) isBaseline(ctx context.Context, pod *v1.Pod, baselinePercent int32, sentinel string) (bool, error) {
+	pods, err := r.listOwnerPods(ctx, pod)
+	if err != nil || pods == nil {
+		return util.IsBaselinePod(pod, baselinePercent, sentinel)
+	}
+
+	index, ok := indexOf(pods, pod.UID)
+	if !ok {
+		return util.IsBaselinePod(pod, baselinePercent, sentinel)
+	}
+
+	return index < baselineCutoff(len(pods), baselinePercent), nil
+}
+
+// isExtendedBaseline is the ServiceExtendedIndicator analogue of isBaseline.
+func (r *baselineResolver) isExtendedBaseline(ctx context.Context, pod *v1.Pod, baselinePercent int32, sentinel, indicatorName string) (bool, error) {
+	pods, err := r.listOwnerPods(ctx, pod)
+	if err != nil || pods == nil {
+		return util.IsExtendedBaselinePod(pod, baselinePercent, sentinel, indicatorName)
+	}
+
+	index, ok := indexOf(pods, pod.UID)
+	if !ok {
+		return util.IsExtendedBaselinePod(pod, baselinePercent, sentinel, indicatorName)
+	}
+
+	return index < baselineCutoff(len(pods), baselinePercent), nil
+}
+
+// listBaselinePods returns the current live baseline pods for workload at baselinePercent, for
+// observability. It requires the SPDFetcher to support listing pods by owner.
+func (r *baselineResolver) listBaselinePods(ctx context.Context, workload metav1.Object, baselinePercent int32) ([]*v1.Pod, error) {
+	if r.lister == nil {
+		return nil, nil
+	}
+
+	pods, err := r.lister.ListPodsForOwner(ctx, workload.GetNamespace(), workload.GetUID())
+	if err != nil {
+		return nil, err
+	}
+
+	sortPodsDeterministically(pods)
+	cutoff := baselineCutoff(len(pods), baselinePercent)
+	if cutoff > len(pods) {
+		cutoff = len(pods)
+	}
+	return pods[:cutoff], nil
+}
+
+// listOwnerPods returns pod's controller owner's live pods, sorted deterministically, or nil if
+// the owner cannot be resolved or the fetcher does not support listing by owner.
+func (r *baselineResolver) listOwnerPods(ctx context.Context, pod *v1.Pod) ([]*v1.Pod, error) {
+	if r.lister == nil {
+		return nil, nil
+	}
+
+	owner := controllerOwnerOf(pod)
+	if owner == nil {
+		return nil, nil
+	}
+
+	pods, err := r.lister.ListPodsForOwner(ctx, pod.Namespace, owner.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	sortPodsDeterministically(pods)
+	return pods, nil
+}
+
+// controllerOwnerOf returns pod's controller owner reference, or nil if it has none (e.g. a bare
+// pod not managed by any workload controller).
+func controllerOwnerOf(pod *v1.Pod) *metav1.OwnerReference {
+	for i := range pod.OwnerReferences {
+		if pod.OwnerReferences[i].Controller != nil && *pod.OwnerReferences[i].Controller {
+			return &pod.OwnerReferences[i]
+		}
+	}
+	return nil
+}
+
+// sortPodsDeterministically orders pods by (creationTimestamp, UID) so that the baseline cutoff
+// is stable across repeated calls and across rolling updates: older pods stay baseline until they
+// are actually terminated.
+func sortPodsDeterministically(pods []*v1.Pod) {
+	sort.Slice(pods, func(i, j int) bool {
+		ti, tj := pods[i].CreationTimestamp, pods[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return pods[i].UID < pods[j].UID
+	})
+}
+
+func indexOf(pods []*v1.Pod, uid types.UID) (int, bool) {
+	for i, pod := range pods {
+		if pod.UID == uid {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// baselineCutoff returns the number of pods, out of total, that are baseline at baselinePercent:
+// a pod is baseline iff its index in the deterministically sorted pod list is below this cutoff.
+func baselineCutoff(total int, baselinePercent int32) int {
+	return int(math.Ceil(float64(total) * float64(baselinePercent) / 100))
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestBaselineCutoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		total           int
+		baselinePercent int32
+		want            int
+	}{
+		{name: "zero percent", total: 10, baselinePercent: 0, want: 0},
+		{name: "hundred percent", total: 10, baselinePercent: 100, want: 10},
+		{name: "rounds up", total: 10, baselinePercent: 25, want: 3},
+		{name: "empty set", total: 0, baselinePercent: 50, want: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := baselineCutoff(tt.total, tt.baselinePercent); got != tt.want {
+				t.Errorf("baselineCutoff(%d, %d) = %d, want %d", tt.total, tt.baselinePercent, got, tt.want)
+			}
+		})
+	}
+}
+
+func podWithUID(uid string, createdAt time.Time) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid), CreationTimestamp: metav1.NewTime(createdAt)}}
+}
+
+func TestSortPodsDeterministically(t *testing.T) {
+	t.Parallel()
+
+	base := time.Unix(1000, 0)
+	pods := []*v1.Pod{
+		podWithUID("b", base),
+		podWithUID("a", base),
+		podWithUID("c", base.Add(-time.Minute)),
+	}
+
+	sortPodsDeterministically(pods)
+
+	want := []string{"c", "a", "b"} // oldest first, ties broken by UID.
+	for i, uid := range want {
+		if string(pods[i].UID) != uid {
+			t.Errorf("pods[%d].UID = %q, want %q", i, pods[i].UID, uid)
+		}
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	t.Parallel()
+
+	pods := []*v1.Pod{podWithUID("a", time.Unix(0, 0)), podWithUID("b", time.Unix(0, 0))}
+
+	if idx, ok := indexOf(pods, types.UID("b")); !ok || idx != 1 {
+		t.Errorf("indexOf(b) = (%d, %v), want (1, true)", idx, ok)
+	}
+
+	if _, ok := indexOf(pods, types.UID("missing")); ok {
+		t.Error("indexOf(missing) found a pod, want not found")
+	}
+}
+
+func TestControllerOwnerOf(t *testing.T) {
+	t.Parallel()
+
+	truth := true
+	owned := &v1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{
+		{UID: types.UID("not-controller")},
+		{UID: types.UID("owner"), Controller: &truth},
+	}}}
+
+	owner := controllerOwnerOf(owned)
+	if owner == nil || owner.UID != types.UID("owner") {
+		t.Errorf("controllerOwnerOf() = %v, want owner UID %q", owner, "owner")
+	}
+
+	if got := controllerOwnerOf(&v1.Pod{}); got != nil {
+		t.Errorf("controllerOwnerOf() for a bare pod = %v, want nil", got)
+	}
+}
+
+type fakePodOwnerLister struct {
+	pods []*v1.Pod
+	err  error
+}
+
+func (f *fakePodOwnerLister) ListPodsForOwner(_ context.Context, _ string, _ types.UID) ([]*v1.Pod, error) {
+	return f.pods, f.err
+}
+
+func TestBaselineResolver_ListBaselinePods(t *testing.T) {
+	t.Parallel()
+
+	base := time.Unix(1000, 0)
+	lister := &fakePodOwnerLister{pods: []*v1.Pod{
+		podWithUID("c", base.Add(2*time.Minute)),
+		podWithUID("a", base),
+		podWithUID("b", base.Add(time.Minute)),
+	}}
+	resolver := &baselineResolver{lister: lister}
+
+	workload := &metav1.ObjectMeta{Namespace: "ns", UID: types.UID("owner")}
+	pods, err := resolver.listBaselinePods(context.Background(), workload, 50)
+	if err != nil {
+		t.Fatalf("listBaselinePods() unexpected error: %v", err)
+	}
+
+	if len(pods) != 2 {
+		t.Fatalf("listBaselinePods() returned %d pods, want 2", len(pods))
+	}
+	if pods[0].UID != types.UID("a") || pods[1].UID != types.UID("b") {
+		t.Errorf("listBaselinePods() = %v, want the two oldest pods [a, b]", pods)
+	}
+}
+
+func TestBaselineResolver_ListBaselinePods_NoLister(t *testing.T) {
+	t.Parallel()
+
+	resolver := &baselineResolver{}
+	workload := &metav1.ObjectMeta{Namespace: "ns", UID: types.UID("owner")}
+
+	pods, err := resolver.listBaselinePods(context.Background(), workload, 50)
+	if err != nil || pods != nil {
+		t.Errorf("listBaselinePods() without a lister = (%v, %v), want (nil, nil)", pods, err)
+	}
+}
+
+func TestBaselineResolver_IsBaseline_UsesLiveIndex(t *testing.T) {
+	t.Parallel()
+
+	base := time.Unix(1000, 0)
+	truth := true
+	target := podWithUID("target", base.Add(time.Minute))
+	target.OwnerReferences = []metav1.OwnerReference{{UID: types.UID("owner"), Controller: &truth}}
+
+	other := podWithUID("other", base)
+	other.OwnerReferences = target.OwnerReferences
+
+	lister := &fakePodOwnerLister{pods: []*v1.Pod{other, target}}
+	resolver := &baselineResolver{lister: lister}
+
+	isBaseline, err := resolver.isBaseline(context.Background(), target, 50, "sentinel")
+	if err != nil {
+		t.Fatalf("isBaseline() unexpected error: %v", err)
+	}
+	// other is older, so at 50% of 2 pods (cutoff 1) only other is baseline; target is not.
+	if isBaseline {
+		t.Error("isBaseline() = true for the newer pod, want false")
+	}
+
+	isBaseline, err = resolver.isBaseline(context.Background(), other, 50, "sentinel")
+	if err != nil {
+		t.Fatalf("isBaseline() unexpected error: %v", err)
+	}
+	if !isBaseline {
+		t.Error("isBaseline() = false for the older pod, want true")
+	}
+}
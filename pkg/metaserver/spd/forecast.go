@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubewharf/katalyst-core/pkg/util"
+	externalspd "github.com/kubewharf/katalyst-core/pkg/util/external/spd"
+)
+
+const (
+	// forecastSeasonDuration is the real-world period (a day) that one Holt-Winters season should
+	// span. The number of samples that make up a season depends on the historical provider's own
+	// sampling interval, so it is derived from the fetched samples rather than assumed to be
+	// forecastResolution (see seasonLengthFor).
+	forecastSeasonDuration = 24 * time.Hour
+	// forecastTrainWindow is how far back historical samples are fetched to fit a predictor. It
+	// must be at least 2*forecastSeasonDuration: PickPredictor only selects Holt-Winters once it
+	// sees at least two full seasons of samples, so fetching exactly one season's worth (as this
+	// used to) made that gate permanently unsatisfiable and silently forced every forecast onto
+	// the FFT fallback regardless of how periodic the real data was.
+	forecastTrainWindow = 2 * forecastSeasonDuration
+	// forecastResolution is the spacing between forecast points within the requested horizon.
+	forecastResolution = time.Minute
+	// forecastRefitInterval is how long a fitted model is reused before refitting against fresh history.
+	forecastRefitInterval = 10 * time.Minute
+)
+
+type forecastKey struct {
+	podUID    types.UID
+	indicator string
+}
+
+type forecastModel struct {
+	predictor Predictor
+	fittedAt  time.Time
+}
+
+// forecaster fits and caches per-(pod, indicator) Predictor models so that
+// ServiceBusinessPerformanceLevelForecast does not refit on every call.
+type forecaster struct {
+	history externalspd.HistoricalIndicatorValueProvider
+
+	mu     sync.Mutex
+	models map[forecastKey]*forecastModel
+}
+
+func newForecaster(history externalspd.HistoricalIndicatorValueProvider) *forecaster {
+	return &forecaster{
+		history: history,
+		models:  make(map[forecastKey]*forecastModel),
+	}
+}
+
+// forecastIndicator returns point predictions for indicatorName at forecastResolution over
+// [now, now+horizon], plus a confidence score derived from the model's training RMSE normalized
+// against the indicator's target window width.
+func (f *forecaster) forecastIndicator(ctx context.Context, pod *v1.Pod, indicatorName string, target util.IndicatorTarget, horizon time.Duration, now time.Time) ([]float64, float64, error) {
+	if f.history == nil {
+		return nil, 0, fmt.Errorf("no historical indicator value provider configured")
+	}
+
+	key := forecastKey{podUID: pod.UID, indicator: indicatorName}
+
+	f.mu.Lock()
+	model, ok := f.models[key]
+	f.mu.Unlock()
+
+	if !ok || now.Sub(model.fittedAt) >= forecastRefitInterval {
+		samples, err := f.history.GetBusinessIndicatorHistory(ctx, pod, indicatorName, forecastTrainWindow)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		predictor := PickPredictor(samples, seasonLengthFor(samples))
+		if err := predictor.Fit(samples); err != nil {
+			return nil, 0, err
+		}
+
+		model = &forecastModel{predictor: predictor, fittedAt: now}
+		f.mu.Lock()
+		f.models[key] = model
+		f.mu.Unlock()
+	}
+
+	var ats []time.Time
+	for t := now; !t.After(now.Add(horizon)); t = t.Add(forecastResolution) {
+		ats = append(ats, t)
+	}
+
+	values, err := model.predictor.Predict(ats)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return values, confidenceFromRMSE(model.predictor.TrainingRMSE(), target), nil
+}
+
+// seasonLengthFor derives the number of samples that make up one forecastSeasonDuration season
+// from the actual spacing between samples, rather than assuming forecastResolution: a historical
+// provider is free to sample at any interval (e.g. a Prometheus-backed provider's own Step), and
+// using the wrong interval would make Holt-Winters bucket samples into the wrong season index.
+// Returns 0 (no reliable season estimate) if there are fewer than two samples to measure from.
+func seasonLengthFor(samples []externalspd.TimeSeriesSample) int {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	interval := samples[1].Timestamp.Sub(samples[0].Timestamp)
+	if interval <= 0 {
+		return 0
+	}
+
+	return int(forecastSeasonDuration / interval)
+}
+
+// confidenceFromRMSE derives a [0,1] confidence score from a model's training RMSE, normalized
+// against the width of the indicator's target window: a model whose typical error is a small
+// fraction of the window is trustworthy, one whose error rivals or exceeds the window is not.
+func confidenceFromRMSE(rmse float64, target util.IndicatorTarget) float64 {
+	width := targetWindowWidth(target)
+	if width <= 0 {
+		return 0
+	}
+
+	confidence := 1 - rmse/width
+	if confidence < 0 {
+		return 0
+	} else if confidence > 1 {
+		return 1
+	}
+	return confidence
+}
+
+func targetWindowWidth(target util.IndicatorTarget) float64 {
+	switch {
+	case target.UpperBound != nil && target.LowerBound != nil:
+		return *target.UpperBound - *target.LowerBound
+	case target.UpperBound != nil:
+		return *target.UpperBound
+	case target.LowerBound != nil:
+		return *target.LowerBound
+	default:
+		return 0
+	}
+}
+
+// classifyIndicatorLevel applies the same target-window rules ServiceBusinessPerformanceLevelRaw
+// uses to a single indicator value, so the logic can be shared with forecast classification.
+func classifyIndicatorLevel(target util.IndicatorTarget, value float64) PerformanceLevel {
+	if target.UpperBound != nil && value > *target.UpperBound {
+		return PerformanceLevelPoor
+	} else if target.LowerBound != nil && value < *target.LowerBound {
+		return PerformanceLevelPerfect
+	}
+	return PerformanceLevelGood
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/katalyst-core/pkg/util"
+	externalspd "github.com/kubewharf/katalyst-core/pkg/util/external/spd"
+)
+
+// fakeHistoryProvider serves a synthetic daily-periodic history for GetBusinessIndicatorHistory,
+// sampled every step over [now-lookback, now], so tests can drive the real
+// forecaster.forecastIndicator path (including forecastTrainWindow/seasonLengthFor) end to end
+// instead of only exercising PickPredictor/HoltWintersPredictor with hand-picked counts.
+type fakeHistoryProvider struct {
+	step time.Time
+	// period is the real-world duration of one repeating cycle in the synthetic series.
+	stepInterval time.Duration
+	period       time.Duration
+}
+
+func (f *fakeHistoryProvider) GetBusinessIndicatorHistory(_ context.Context, _ *v1.Pod, _ string, lookback time.Duration) ([]externalspd.TimeSeriesSample, error) {
+	n := int(lookback / f.stepInterval)
+	start := f.step.Add(-lookback)
+
+	samples := make([]externalspd.TimeSeriesSample, n)
+	for i := 0; i < n; i++ {
+		t := start.Add(time.Duration(i) * f.stepInterval)
+		phase := float64(t.Sub(start)%f.period) / float64(f.period)
+		value := 10.0
+		if phase >= 0.5 {
+			value = 20.0
+		}
+		samples[i] = externalspd.TimeSeriesSample{Timestamp: t, Value: value}
+	}
+	return samples, nil
+}
+
+func TestSeasonLengthFor_MatchesForecastSeasonDuration(t *testing.T) {
+	t.Parallel()
+
+	provider := &fakeHistoryProvider{step: time.Unix(0, 0), stepInterval: time.Minute, period: 24 * time.Hour}
+	samples, err := provider.GetBusinessIndicatorHistory(context.Background(), nil, "qps", forecastTrainWindow)
+	if err != nil {
+		t.Fatalf("GetBusinessIndicatorHistory() unexpected error: %v", err)
+	}
+
+	wantSeasonLength := int(forecastSeasonDuration / time.Minute)
+	if got := seasonLengthFor(samples); got != wantSeasonLength {
+		t.Fatalf("seasonLengthFor() = %d, want %d", got, wantSeasonLength)
+	}
+
+	// forecastTrainWindow must fetch at least two full seasons, or PickPredictor's
+	// len(samples) >= 2*seasonLength gate can never be satisfied and Holt-Winters becomes
+	// unreachable in real wiring regardless of how periodic the data is.
+	if len(samples) < 2*wantSeasonLength {
+		t.Fatalf("forecastTrainWindow yields %d samples for a %d season length, want at least %d (2 seasons)",
+			len(samples), wantSeasonLength, 2*wantSeasonLength)
+	}
+}
+
+func TestForecaster_ForecastIndicator_PicksHoltWintersThroughRealConstants(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0).Add(30 * 24 * time.Hour) // comfortably past forecastTrainWindow of history
+	provider := &fakeHistoryProvider{step: now, stepInterval: time.Minute, period: 24 * time.Hour}
+	f := newForecaster(provider)
+
+	pod := &v1.Pod{}
+	target := util.IndicatorTarget{LowerBound: pointer.Float64(0), UpperBound: pointer.Float64(100)}
+
+	values, confidence, err := f.forecastIndicator(context.Background(), pod, "qps", target, time.Hour, now)
+	if err != nil {
+		t.Fatalf("forecastIndicator() unexpected error: %v", err)
+	}
+	if len(values) == 0 {
+		t.Fatal("forecastIndicator() returned no predicted values")
+	}
+	if confidence <= 0 {
+		t.Errorf("forecastIndicator() confidence = %v, want > 0 for a cleanly periodic series", confidence)
+	}
+
+	model, ok := f.models[forecastKey{podUID: pod.UID, indicator: "qps"}]
+	if !ok {
+		t.Fatal("forecastIndicator() did not cache a fitted model")
+	}
+	if _, isHoltWinters := model.predictor.(*HoltWintersPredictor); !isHoltWinters {
+		t.Errorf("forecastIndicator() fitted a %T, want *HoltWintersPredictor given forecastTrainWindow now covers two full seasons", model.predictor)
+	}
+}
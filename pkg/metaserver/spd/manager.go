@@ -19,16 +19,21 @@ package spd
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 
 	workloadapis "github.com/kubewharf/katalyst-api/pkg/apis/workload/v1alpha1"
 	"github.com/kubewharf/katalyst-core/pkg/util"
+	externalspd "github.com/kubewharf/katalyst-core/pkg/util/external/spd"
 )
 
 // PerformanceLevel is an enumeration type, the smaller the
@@ -48,9 +53,36 @@ const (
 type IndicatorTarget map[string]util.IndicatorTarget
 
 type ServiceProfilingManager interface {
-	// ServiceBusinessPerformanceLevel returns the service business performance level for the given pod
+	// ServiceBusinessPerformanceLevel returns the smoothed service business performance level for
+	// the given pod. Smoothing is a no-op until ConfigureSmoothing is called with Enabled: true.
 	ServiceBusinessPerformanceLevel(ctx context.Context, pod *v1.Pod) (PerformanceLevel, error)
 
+	// ServiceBusinessPerformanceLevelRaw returns the unsmoothed service business performance
+	// level, i.e. what ServiceBusinessPerformanceLevel would have returned before smoothing.
+	ServiceBusinessPerformanceLevelRaw(ctx context.Context, pod *v1.Pod) (PerformanceLevel, error)
+
+	// ConfigureSmoothing updates the hysteresis/dwell-time smoothing applied to
+	// ServiceBusinessPerformanceLevel.
+	ConfigureSmoothing(options SmoothingOptions)
+
+	// ServiceBusinessPerformanceLevelForecast returns the predicted poorest service business
+	// performance level within horizon, plus a confidence score in [0,1], so callers can act
+	// before an SLO violation actually happens instead of after.
+	ServiceBusinessPerformanceLevelForecast(ctx context.Context, pod *v1.Pod, horizon time.Duration) (PerformanceLevel, float64, error)
+
+	// Subscribe returns a channel of ProfileEvent for pods matching selector, emitted whenever
+	// a pod's business performance level or baseline membership changes, plus a CancelFunc that
+	// unsubscribes and closes the channel.
+	Subscribe(selector labels.Selector) (<-chan ProfileEvent, CancelFunc)
+
+	// SubscribeExtendedIndicator is like Subscribe, but emits when the named extended indicator
+	// changes by at least delta, as measured by distance.
+	SubscribeExtendedIndicator(name string, selector labels.Selector, delta float64, distance ExtendedIndicatorDistance) (<-chan ProfileEvent, CancelFunc)
+
+	// ListBaselinePods returns the current live baseline pods for workload at baselinePercent,
+	// computed from the workload's actual running pods rather than its controller template.
+	ListBaselinePods(ctx context.Context, workload metav1.Object, baselinePercent int32) ([]*v1.Pod, error)
+
 	// ServiceBusinessPerformanceScore returns the service business performance score for the given pod
 	// The score is in range [MinPerformanceScore, MaxPerformanceScore]
 	ServiceBusinessPerformanceScore(ctx context.Context, pod *v1.Pod) (float64, error)
@@ -75,6 +107,8 @@ type DummyPodServiceProfile struct {
 
 type DummyServiceProfilingManager struct {
 	podProfiles map[types.UID]DummyPodServiceProfile
+
+	subscriptions *subscriptionRegistry
 }
 
 func (d *DummyServiceProfilingManager) ServiceExtendedIndicator(_ context.Context, _ *v1.Pod, _ interface{}) (bool, error) {
@@ -86,7 +120,35 @@ func (d *DummyServiceProfilingManager) ServiceBaseline(_ context.Context, _ *v1.
 }
 
 func NewDummyServiceProfilingManager(podProfiles map[types.UID]DummyPodServiceProfile) *DummyServiceProfilingManager {
-	return &DummyServiceProfilingManager{podProfiles: podProfiles}
+	return &DummyServiceProfilingManager{
+		podProfiles:   podProfiles,
+		subscriptions: newSubscriptionRegistry(defaultSubscriptionBufferSize),
+	}
+}
+
+func (d *DummyServiceProfilingManager) Subscribe(selector labels.Selector) (<-chan ProfileEvent, CancelFunc) {
+	return d.subscriptions.subscribe(selector)
+}
+
+func (d *DummyServiceProfilingManager) SubscribeExtendedIndicator(name string, selector labels.Selector, delta float64, distance ExtendedIndicatorDistance) (<-chan ProfileEvent, CancelFunc) {
+	return d.subscriptions.subscribeExtendedIndicator(name, selector, delta, distance)
+}
+
+// EmitProfileEvent is a test helper that synthesizes a business performance level/baseline
+// transition for pod and fans it out to matching Subscribe subscribers, without requiring a real
+// SPDFetcher update.
+func (d *DummyServiceProfilingManager) EmitProfileEvent(pod *v1.Pod, level PerformanceLevel, baseline bool) {
+	d.subscriptions.notify(pod, level, baseline, nil, time.Now())
+}
+
+// EmitExtendedIndicatorEvent is a test helper that synthesizes an extended indicator value change
+// for pod and fans it out to matching SubscribeExtendedIndicator subscribers.
+func (d *DummyServiceProfilingManager) EmitExtendedIndicatorEvent(pod *v1.Pod, indicatorName string, value interface{}) {
+	d.subscriptions.notifyExtendedIndicator(pod, indicatorName, value, time.Now())
+}
+
+func (d *DummyServiceProfilingManager) ListBaselinePods(_ context.Context, _ metav1.Object, _ int32) ([]*v1.Pod, error) {
+	return nil, nil
 }
 
 func (d *DummyServiceProfilingManager) ServiceBusinessPerformanceLevel(_ context.Context, pod *v1.Pod) (PerformanceLevel, error) {
@@ -97,6 +159,17 @@ func (d *DummyServiceProfilingManager) ServiceBusinessPerformanceLevel(_ context
 	return profile.PerformanceLevel, nil
 }
 
+func (d *DummyServiceProfilingManager) ServiceBusinessPerformanceLevelRaw(ctx context.Context, pod *v1.Pod) (PerformanceLevel, error) {
+	return d.ServiceBusinessPerformanceLevel(ctx, pod)
+}
+
+func (d *DummyServiceProfilingManager) ConfigureSmoothing(_ SmoothingOptions) {}
+
+func (d *DummyServiceProfilingManager) ServiceBusinessPerformanceLevelForecast(ctx context.Context, pod *v1.Pod, _ time.Duration) (PerformanceLevel, float64, error) {
+	level, err := d.ServiceBusinessPerformanceLevel(ctx, pod)
+	return level, 0, err
+}
+
 func (d *DummyServiceProfilingManager) ServiceBusinessPerformanceScore(_ context.Context, pod *v1.Pod) (float64, error) {
 	profile, ok := d.podProfiles[pod.UID]
 	if !ok {
@@ -115,6 +188,95 @@ var _ ServiceProfilingManager = &DummyServiceProfilingManager{}
 
 type serviceProfilingManager struct {
 	fetcher SPDFetcher
+
+	// valueProvider is consulted for a business/extended indicator's current value whenever the
+	// SPD status does not already have one, e.g. because no external reconciler wrote it there.
+	// It is optional: a nil valueProvider preserves the previous SPD-status-only behavior.
+	valueProvider externalspd.IndicatorValueProvider
+
+	// smoother applies hysteresis/dwell-time smoothing on top of the raw performance level.
+	smoother *levelSmoother
+
+	// forecaster fits and caches per-indicator predictors used by ServiceBusinessPerformanceLevelForecast.
+	forecaster *forecaster
+
+	// subscriptions fans out ProfileEvents to Subscribe/SubscribeExtendedIndicator callers.
+	subscriptions *subscriptionRegistry
+
+	// baseline resolves baseline membership from the workload's live pods rather than its
+	// controller template, falling back to the sentinel-hash path when that is not possible.
+	baseline *baselineResolver
+}
+
+// ServiceProfilingManagerOption customizes a serviceProfilingManager at construction time.
+type ServiceProfilingManagerOption func(*serviceProfilingManager)
+
+// WithIndicatorValueProvider configures the manager to fall back to the given
+// IndicatorValueProvider when an indicator value is missing from the SPD status.
+func WithIndicatorValueProvider(provider externalspd.IndicatorValueProvider) ServiceProfilingManagerOption {
+	return func(m *serviceProfilingManager) {
+		m.valueProvider = provider
+	}
+}
+
+// WithSubscriptionBufferSize overrides the per-subscriber channel buffer size used by Subscribe
+// and SubscribeExtendedIndicator.
+func WithSubscriptionBufferSize(size int) ServiceProfilingManagerOption {
+	return func(m *serviceProfilingManager) {
+		m.subscriptions = newSubscriptionRegistry(size)
+	}
+}
+
+// getBusinessIndicatorValue resolves a business indicator value from the SPD status, falling
+// back to the manager's IndicatorValueProvider (if configured) when the status has no value yet.
+func (m *serviceProfilingManager) getBusinessIndicatorValue(ctx context.Context, pod *v1.Pod, spdIndicatorValue map[string]float64, indicatorName string) (float64, bool) {
+	if value, ok := spdIndicatorValue[indicatorName]; ok {
+		return value, true
+	}
+
+	if m.valueProvider == nil {
+		return 0, false
+	}
+
+	value, _, err := m.valueProvider.GetBusinessIndicatorValue(ctx, pod, indicatorName)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// fillExtendedIndicatorFromProvider populates indicators' float64 fields from the manager's
+// IndicatorValueProvider when the SPD status has not recorded a value for this extended indicator
+// yet, mirroring the fallback getBusinessIndicatorValue applies to business indicators. It reports
+// whether it managed to fill at least one field.
+func (m *serviceProfilingManager) fillExtendedIndicatorFromProvider(ctx context.Context, pod *v1.Pod, name string, indicators interface{}) bool {
+	if m.valueProvider == nil {
+		return false
+	}
+
+	t := reflect.TypeOf(indicators)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+
+	v := reflect.ValueOf(indicators).Elem()
+	filled := false
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Float64 || !field.CanSet() {
+			continue
+		}
+
+		value, _, err := m.valueProvider.GetExtendedIndicatorValue(ctx, pod, name, t.Elem().Field(i).Name)
+		if err != nil {
+			continue
+		}
+
+		field.SetFloat(value)
+		filled = true
+	}
+
+	return filled
 }
 
 func (m *serviceProfilingManager) ServiceExtendedIndicator(ctx context.Context, pod *v1.Pod, indicators interface{}) (bool, error) {
@@ -140,7 +302,10 @@ func (m *serviceProfilingManager) ServiceExtendedIndicator(ctx context.Context,
 
 		object := indicator.Indicators.Object
 		if object == nil {
-			return false, fmt.Errorf("%s inidators object is nil", name)
+			if !m.fillExtendedIndicatorFromProvider(ctx, pod, name, indicators) {
+				return false, fmt.Errorf("%s inidators object is nil", name)
+			}
+			return m.baseline.isExtendedBaseline(ctx, pod, indicator.BaselinePercent, extendedBaselineSentinel, name)
 		}
 
 		t := reflect.TypeOf(indicators)
@@ -154,13 +319,75 @@ func (m *serviceProfilingManager) ServiceExtendedIndicator(ctx context.Context,
 		}
 
 		reflect.ValueOf(indicators).Elem().Set(v.Convert(t).Elem())
-		return util.IsExtendedBaselinePod(pod, indicator.BaselinePercent, extendedBaselineSentinel, name)
+		return m.baseline.isExtendedBaseline(ctx, pod, indicator.BaselinePercent, extendedBaselineSentinel, name)
 	}
 
 	return false, errors.NewNotFound(schema.GroupResource{Group: workloadapis.GroupName,
 		Resource: strings.ToLower(o.GetObjectKind().GroupVersionKind().Kind)}, name)
 }
 
+func (m *serviceProfilingManager) Subscribe(selector labels.Selector) (<-chan ProfileEvent, CancelFunc) {
+	return m.subscriptions.subscribe(selector)
+}
+
+func (m *serviceProfilingManager) SubscribeExtendedIndicator(name string, selector labels.Selector, delta float64, distance ExtendedIndicatorDistance) (<-chan ProfileEvent, CancelFunc) {
+	return m.subscriptions.subscribeExtendedIndicator(name, selector, delta, distance)
+}
+
+// handleSPDUpdate recomputes pod's business performance level, baseline membership and extended
+// indicator values, and fans out any changes to Subscribe/SubscribeExtendedIndicator callers. It
+// is invoked whenever the SPDFetcher notifies the manager of an SPD update for pod.
+func (m *serviceProfilingManager) handleSPDUpdate(ctx context.Context, pod *v1.Pod) {
+	now := time.Now()
+
+	if level, err := m.ServiceBusinessPerformanceLevelRaw(ctx, pod); err == nil {
+		baseline, err := m.ServiceBaseline(ctx, pod)
+		if err != nil {
+			baseline = false
+		}
+		m.subscriptions.notify(pod, level, baseline, m.businessIndicatorLevels(ctx, pod), now)
+	}
+
+	spd, err := m.fetcher.GetSPD(ctx, pod)
+	if err != nil {
+		return
+	}
+
+	for _, indicator := range spd.Spec.ExtendedIndicator {
+		if indicator.Indicators.Object != nil {
+			m.subscriptions.notifyExtendedIndicator(pod, indicator.Name, indicator.Indicators.Object, now)
+		}
+	}
+}
+
+// businessIndicatorLevels recomputes the per-indicator performance levels for pod, used to
+// populate ProfileEvent.ChangedIndicators. Errors are swallowed: a missing indicator value simply
+// does not contribute to the diff.
+func (m *serviceProfilingManager) businessIndicatorLevels(ctx context.Context, pod *v1.Pod) map[string]PerformanceLevel {
+	spd, err := m.fetcher.GetSPD(ctx, pod)
+	if err != nil {
+		return nil
+	}
+
+	indicatorTarget, err := util.GetServiceBusinessIndicatorTarget(spd)
+	if err != nil {
+		return nil
+	}
+
+	indicatorValue, err := util.GetServiceBusinessIndicatorValue(spd)
+	if err != nil {
+		return nil
+	}
+
+	levels := make(map[string]PerformanceLevel, len(indicatorTarget))
+	for indicatorName, target := range indicatorTarget {
+		if value, ok := m.getBusinessIndicatorValue(ctx, pod, indicatorValue, indicatorName); ok {
+			levels[indicatorName] = classifyIndicatorLevel(target, value)
+		}
+	}
+	return levels
+}
+
 func (m *serviceProfilingManager) ServiceBaseline(ctx context.Context, pod *v1.Pod) (bool, error) {
 	spd, err := m.fetcher.GetSPD(ctx, pod)
 	if err != nil && !errors.IsNotFound(err) {
@@ -174,7 +401,7 @@ func (m *serviceProfilingManager) ServiceBaseline(ctx context.Context, pod *v1.P
 		return false, err
 	}
 
-	isBaseline, err := util.IsBaselinePod(pod, spd.Spec.BaselinePercent, baselineSentinel)
+	isBaseline, err := m.baseline.isBaseline(ctx, pod, spd.Spec.BaselinePercent, baselineSentinel)
 	if err != nil {
 		return false, err
 	}
@@ -182,20 +409,165 @@ func (m *serviceProfilingManager) ServiceBaseline(ctx context.Context, pod *v1.P
 	return isBaseline, nil
 }
 
-func NewServiceProfilingManager(fetcher SPDFetcher) ServiceProfilingManager {
-	return &serviceProfilingManager{
-		fetcher: fetcher,
+// ListBaselinePods returns workload's current live baseline pods at baselinePercent, for
+// observability.
+func (m *serviceProfilingManager) ListBaselinePods(ctx context.Context, workload metav1.Object, baselinePercent int32) ([]*v1.Pod, error) {
+	return m.baseline.listBaselinePods(ctx, workload, baselinePercent)
+}
+
+func NewServiceProfilingManager(fetcher SPDFetcher, opts ...ServiceProfilingManagerOption) ServiceProfilingManager {
+	m := &serviceProfilingManager{
+		fetcher:       fetcher,
+		smoother:      newLevelSmoother(),
+		subscriptions: newSubscriptionRegistry(defaultSubscriptionBufferSize),
+		baseline:      newBaselineResolver(fetcher),
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	history, _ := m.valueProvider.(externalspd.HistoricalIndicatorValueProvider)
+	m.forecaster = newForecaster(history)
+
+	return m
 }
 
-func (m *serviceProfilingManager) ServiceBusinessPerformanceScore(_ context.Context, _ *v1.Pod) (float64, error) {
-	// todo: implement service business performance score using spd to calculate
-	return MaxPerformanceScore, nil
+// ServiceBusinessPerformanceScore gets the service business performance score by spd, normalizing
+// each business indicator against its target window and aggregating the per-indicator scores with
+// a weighted generalized mean, so that one poor indicator drags down the overall score in the same
+// way ServiceBusinessPerformanceLevel propagates the poorest level.
+func (m *serviceProfilingManager) ServiceBusinessPerformanceScore(ctx context.Context, pod *v1.Pod) (float64, error) {
+	spd, err := m.fetcher.GetSPD(ctx, pod)
+	if err != nil {
+		return MinPerformanceScore, err
+	}
+
+	indicatorTarget, err := util.GetServiceBusinessIndicatorTarget(spd)
+	if err != nil {
+		return MinPerformanceScore, err
+	}
+
+	indicatorValue, err := util.GetServiceBusinessIndicatorValue(spd)
+	if err != nil {
+		return MinPerformanceScore, err
+	}
+
+	options, err := util.GetServiceBusinessPerformanceScoreOptions(spd)
+	if err != nil {
+		return MinPerformanceScore, err
+	}
+
+	return aggregateIndicatorScores(indicatorTarget, options, func(indicatorName string) (float64, bool) {
+		return m.getBusinessIndicatorValue(ctx, pod, indicatorValue, indicatorName)
+	})
 }
 
-// ServiceBusinessPerformanceLevel gets the service business performance level by spd, and use the poorest business indicator
-// performance level as the service business performance level.
+// aggregateIndicatorScores combines the per-indicator scores in indicatorTarget into a single
+// weighted generalized mean, using getValue to resolve each indicator's current value. It is
+// factored out of ServiceBusinessPerformanceScore so the aggregation math - weighting, the
+// generalized-mean exponent, slack band and permissive handling - can be unit tested without a
+// real SPDFetcher.
+func aggregateIndicatorScores(indicatorTarget map[string]util.IndicatorTarget, options util.ServiceBusinessPerformanceScoreOptions, getValue func(indicatorName string) (float64, bool)) (float64, error) {
+	var weightedSum, weightSum float64
+	for indicatorName, target := range indicatorTarget {
+		value, ok := getValue(indicatorName)
+		if !ok {
+			if options.Permissive {
+				continue
+			}
+			return MinPerformanceScore, fmt.Errorf("indicator %s current value not found", indicatorName)
+		}
+
+		// A weight configured as exactly 0 excludes the indicator; only an unconfigured
+		// (absent) or negative weight falls back to the default of 1. options.Weights[...]'s
+		// zero value can't tell those two cases apart, so check presence explicitly.
+		weight, configured := options.Weights[indicatorName]
+		if !configured || weight < 0 {
+			weight = 1
+		}
+		if weight == 0 {
+			continue
+		}
+
+		score := indicatorScore(target, value, options.SlackBand)
+		weightedSum += weight * math.Pow(score, options.Exponent)
+		weightSum += weight
+	}
+
+	if weightSum == 0 {
+		return MaxPerformanceScore, nil
+	}
+
+	return math.Pow(weightedSum/weightSum, 1/options.Exponent), nil
+}
+
+// indicatorScore normalizes a single observed indicator value against its target window into
+// a score in [MinPerformanceScore, MaxPerformanceScore].
+func indicatorScore(target util.IndicatorTarget, value float64, slackBand float64) float64 {
+	switch {
+	case target.UpperBound != nil && target.LowerBound != nil:
+		upper, lower := *target.UpperBound, *target.LowerBound
+		if value >= lower && value <= upper {
+			return MaxPerformanceScore
+		}
+
+		tolerance := target.Tolerance
+		if tolerance <= 0 {
+			tolerance = upper - lower
+		}
+
+		var overshoot float64
+		if value > upper {
+			overshoot = value - upper
+		} else {
+			overshoot = lower - value
+		}
+
+		return clampScore(MaxPerformanceScore * (1 - overshoot/tolerance))
+	case target.UpperBound != nil:
+		// latency-like indicator: smaller is better, score decays as value approaches and exceeds upper bound.
+		upper := *target.UpperBound * (1 + slackBand)
+		return clampScore(MaxPerformanceScore * (upper - value) / upper)
+	case target.LowerBound != nil:
+		// throughput-like indicator: bigger is better, capped once value reaches the lower bound.
+		lower := *target.LowerBound
+		return clampScore(MaxPerformanceScore * value / lower)
+	default:
+		return MaxPerformanceScore
+	}
+}
+
+func clampScore(score float64) float64 {
+	if score < MinPerformanceScore {
+		return MinPerformanceScore
+	} else if score > MaxPerformanceScore {
+		return MaxPerformanceScore
+	}
+	return score
+}
+
+// ServiceBusinessPerformanceLevel returns the smoothed service business performance level, see
+// ConfigureSmoothing. With smoothing disabled (the default) this is identical to
+// ServiceBusinessPerformanceLevelRaw.
 func (m *serviceProfilingManager) ServiceBusinessPerformanceLevel(ctx context.Context, pod *v1.Pod) (PerformanceLevel, error) {
+	level, err := m.ServiceBusinessPerformanceLevelRaw(ctx, pod)
+	if err != nil {
+		return level, err
+	}
+
+	return m.smoother.smooth(pod.UID, level, time.Now()), nil
+}
+
+// ConfigureSmoothing updates the hysteresis/dwell-time smoothing applied to
+// ServiceBusinessPerformanceLevel.
+func (m *serviceProfilingManager) ConfigureSmoothing(options SmoothingOptions) {
+	m.smoother.configure(options)
+}
+
+// ServiceBusinessPerformanceLevelRaw gets the service business performance level by spd, and use the poorest business indicator
+// performance level as the service business performance level.
+func (m *serviceProfilingManager) ServiceBusinessPerformanceLevelRaw(ctx context.Context, pod *v1.Pod) (PerformanceLevel, error) {
 	spd, err := m.fetcher.GetSPD(ctx, pod)
 	if err != nil {
 		return PerformanceLevelUnknown, err
@@ -213,18 +585,13 @@ func (m *serviceProfilingManager) ServiceBusinessPerformanceLevel(ctx context.Co
 
 	indicatorLevelMap := make(map[string]PerformanceLevel)
 	for indicatorName, target := range indicatorTarget {
-		if _, ok := indicatorValue[indicatorName]; !ok {
+		value, ok := m.getBusinessIndicatorValue(ctx, pod, indicatorValue, indicatorName)
+		if !ok {
 			indicatorLevelMap[indicatorName] = PerformanceLevelUnknown
 			continue
 		}
 
-		if target.UpperBound != nil && indicatorValue[indicatorName] > *target.UpperBound {
-			indicatorLevelMap[indicatorName] = PerformanceLevelPoor
-		} else if target.LowerBound != nil && indicatorValue[indicatorName] < *target.LowerBound {
-			indicatorLevelMap[indicatorName] = PerformanceLevelPerfect
-		} else {
-			indicatorLevelMap[indicatorName] = PerformanceLevelGood
-		}
+		indicatorLevelMap[indicatorName] = classifyIndicatorLevel(target, value)
 	}
 
 	// calculate the poorest performance level of indicator as the final performance level
@@ -244,6 +611,52 @@ func (m *serviceProfilingManager) ServiceBusinessPerformanceLevel(ctx context.Co
 	return result, nil
 }
 
+// ServiceBusinessPerformanceLevelForecast predicts the poorest service business performance
+// level within horizon by fitting a Predictor to each business indicator's historical values and
+// classifying every forecast point against that indicator's target window. It requires a
+// HistoricalIndicatorValueProvider (see WithIndicatorValueProvider) to supply training samples;
+// indicators without history are skipped rather than failing the whole call.
+func (m *serviceProfilingManager) ServiceBusinessPerformanceLevelForecast(ctx context.Context, pod *v1.Pod, horizon time.Duration) (PerformanceLevel, float64, error) {
+	spd, err := m.fetcher.GetSPD(ctx, pod)
+	if err != nil {
+		return PerformanceLevelUnknown, 0, err
+	}
+
+	indicatorTarget, err := util.GetServiceBusinessIndicatorTarget(spd)
+	if err != nil {
+		return PerformanceLevelUnknown, 0, err
+	}
+
+	now := time.Now()
+	result := PerformanceLevelUnknown
+	confidence := 1.0
+	forecastCount := 0
+
+	for indicatorName, target := range indicatorTarget {
+		values, indicatorConfidence, err := m.forecaster.forecastIndicator(ctx, pod, indicatorName, target, horizon, now)
+		if err != nil {
+			continue
+		}
+		forecastCount++
+
+		if indicatorConfidence < confidence {
+			confidence = indicatorConfidence
+		}
+
+		for _, value := range values {
+			if level := classifyIndicatorLevel(target, value); result < level {
+				result = level
+			}
+		}
+	}
+
+	if forecastCount == 0 {
+		return PerformanceLevelUnknown, 0, fmt.Errorf("no business indicator could be forecast for pod %s/%s", pod.Namespace, pod.Name)
+	}
+
+	return result, confidence, nil
+}
+
 // ServiceSystemPerformanceTarget gets the service system performance target by spd and return the indicator name
 // and its upper and lower bounds
 func (m *serviceProfilingManager) ServiceSystemPerformanceTarget(ctx context.Context, pod *v1.Pod) (IndicatorTarget, error) {
@@ -255,6 +668,30 @@ func (m *serviceProfilingManager) ServiceSystemPerformanceTarget(ctx context.Con
 	return util.GetServiceSystemIndicatorTarget(spd)
 }
 
+// podDeleteNotifier is an optional capability an SPDFetcher may implement to let the manager
+// garbage-collect per-pod state (e.g. the level smoother's history) as soon as a pod is deleted,
+// instead of waiting for it to age out.
+type podDeleteNotifier interface {
+	RegisterPodDeleteHandler(handler func(types.UID))
+}
+
+// spdUpdateNotifier is an optional capability an SPDFetcher may implement to let the manager
+// react to SPD updates immediately, instead of requiring Subscribe callers to poll.
+type spdUpdateNotifier interface {
+	RegisterSPDUpdateHandler(handler func(pod *v1.Pod))
+}
+
 func (m *serviceProfilingManager) Run(ctx context.Context) {
+	if notifier, ok := m.fetcher.(podDeleteNotifier); ok {
+		notifier.RegisterPodDeleteHandler(m.smoother.forgetPod)
+	}
+
+	if notifier, ok := m.fetcher.(spdUpdateNotifier); ok {
+		notifier.RegisterSPDUpdateHandler(func(pod *v1.Pod) {
+			m.handleSPDUpdate(ctx, pod)
+		})
+	}
+
+	defer m.subscriptions.closeAll()
 	m.fetcher.Run(ctx)
 }
@@ -0,0 +1,248 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"math"
+	"testing"
+
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/katalyst-core/pkg/util"
+)
+
+func TestIndicatorScore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		target    util.IndicatorTarget
+		value     float64
+		slackBand float64
+		want      float64
+	}{
+		{
+			name:   "two-sided within window scores max",
+			target: util.IndicatorTarget{LowerBound: pointer.Float64(10), UpperBound: pointer.Float64(20)},
+			value:  15,
+			want:   MaxPerformanceScore,
+		},
+		{
+			name:   "two-sided above window decays over the window width when tolerance is unset",
+			target: util.IndicatorTarget{LowerBound: pointer.Float64(10), UpperBound: pointer.Float64(20)},
+			value:  25, // overshoot 5 over a width-10 window
+			want:   50,
+		},
+		{
+			name:   "two-sided below window decays using the explicit tolerance",
+			target: util.IndicatorTarget{LowerBound: pointer.Float64(10), UpperBound: pointer.Float64(20), Tolerance: 20},
+			value:  5, // overshoot 5 over tolerance 20
+			want:   75,
+		},
+		{
+			name:   "two-sided overshoot beyond tolerance clamps to the minimum",
+			target: util.IndicatorTarget{LowerBound: pointer.Float64(10), UpperBound: pointer.Float64(20), Tolerance: 5},
+			value:  30,
+			want:   MinPerformanceScore,
+		},
+		{
+			name:      "upper-bound-only applies slack band before decaying",
+			target:    util.IndicatorTarget{UpperBound: pointer.Float64(100)},
+			value:     100,
+			slackBand: 0.1, // effective upper bound 110, so value==upper still scores above zero
+			want:      MaxPerformanceScore * (110 - 100) / 110,
+		},
+		{
+			name:   "lower-bound-only caps at the bound",
+			target: util.IndicatorTarget{LowerBound: pointer.Float64(50)},
+			value:  75, // above the lower bound, should clamp rather than exceed max
+			want:   MaxPerformanceScore,
+		},
+		{
+			name:   "lower-bound-only scales below the bound",
+			target: util.IndicatorTarget{LowerBound: pointer.Float64(50)},
+			value:  25,
+			want:   50,
+		},
+		{
+			name:   "no bounds always scores max",
+			target: util.IndicatorTarget{},
+			value:  1e9,
+			want:   MaxPerformanceScore,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := indicatorScore(tt.target, tt.value, tt.slackBand); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("indicatorScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateIndicatorScores_HarmonicMean(t *testing.T) {
+	t.Parallel()
+
+	// Two indicators with perfectly-met targets (score 100 each) aggregated with the default
+	// harmonic mean (Exponent: -1) should come back out at 100.
+	target := map[string]util.IndicatorTarget{
+		"cpu":     {LowerBound: pointer.Float64(0), UpperBound: pointer.Float64(10)},
+		"latency": {LowerBound: pointer.Float64(0), UpperBound: pointer.Float64(10)},
+	}
+	options := util.ServiceBusinessPerformanceScoreOptions{Weights: map[string]float64{}, Exponent: -1}
+
+	got, err := aggregateIndicatorScores(target, options, func(indicatorName string) (float64, bool) {
+		return 5, true
+	})
+	if err != nil {
+		t.Fatalf("aggregateIndicatorScores() unexpected error: %v", err)
+	}
+	if math.Abs(got-MaxPerformanceScore) > 1e-9 {
+		t.Errorf("aggregateIndicatorScores() = %v, want %v", got, MaxPerformanceScore)
+	}
+}
+
+func TestAggregateIndicatorScores_PoorIndicatorDragsDownHarmonicMean(t *testing.T) {
+	t.Parallel()
+
+	target := map[string]util.IndicatorTarget{
+		"good": {UpperBound: pointer.Float64(100)},
+		"poor": {UpperBound: pointer.Float64(100)},
+	}
+	options := util.ServiceBusinessPerformanceScoreOptions{Weights: map[string]float64{}, Exponent: -1}
+	values := map[string]float64{"good": 0, "poor": 99}
+
+	got, err := aggregateIndicatorScores(target, options, func(indicatorName string) (float64, bool) {
+		return values[indicatorName], true
+	})
+	if err != nil {
+		t.Fatalf("aggregateIndicatorScores() unexpected error: %v", err)
+	}
+
+	// Harmonic mean of 100 and 1 is much closer to 1 than the arithmetic mean of 50.5 would be.
+	if got >= 50 {
+		t.Errorf("aggregateIndicatorScores() = %v, want well below the arithmetic mean of 50.5 since harmonic mean should be dragged down by the poor indicator", got)
+	}
+}
+
+func TestAggregateIndicatorScores_ExplicitZeroWeightExcludesIndicator(t *testing.T) {
+	t.Parallel()
+
+	target := map[string]util.IndicatorTarget{
+		"excluded": {UpperBound: pointer.Float64(100)},
+		"counted":  {UpperBound: pointer.Float64(100)},
+	}
+	values := map[string]float64{"excluded": 99, "counted": 0}
+	options := util.ServiceBusinessPerformanceScoreOptions{
+		Weights:  map[string]float64{"excluded": 0},
+		Exponent: -1,
+	}
+
+	got, err := aggregateIndicatorScores(target, options, func(indicatorName string) (float64, bool) {
+		return values[indicatorName], true
+	})
+	if err != nil {
+		t.Fatalf("aggregateIndicatorScores() unexpected error: %v", err)
+	}
+
+	// If the explicit weight of 0 were ignored (treated as "unconfigured"), the poor "excluded"
+	// indicator would drag the harmonic mean down well below max.
+	if math.Abs(got-MaxPerformanceScore) > 1e-9 {
+		t.Errorf("aggregateIndicatorScores() = %v, want %v: an explicit weight of 0 should exclude the indicator entirely", got, MaxPerformanceScore)
+	}
+}
+
+func TestAggregateIndicatorScores_UnconfiguredWeightDefaultsToOne(t *testing.T) {
+	t.Parallel()
+
+	target := map[string]util.IndicatorTarget{
+		"a": {UpperBound: pointer.Float64(100)},
+		"b": {UpperBound: pointer.Float64(100)},
+	}
+	options := util.ServiceBusinessPerformanceScoreOptions{Weights: map[string]float64{}, Exponent: -1}
+	values := map[string]float64{"a": 0, "b": 50}
+
+	got, err := aggregateIndicatorScores(target, options, func(indicatorName string) (float64, bool) {
+		return values[indicatorName], true
+	})
+	if err != nil {
+		t.Fatalf("aggregateIndicatorScores() unexpected error: %v", err)
+	}
+
+	want := math.Pow((math.Pow(MaxPerformanceScore, -1)+math.Pow(50, -1))/2, -1)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("aggregateIndicatorScores() = %v, want %v (equal weight 1 for both indicators)", got, want)
+	}
+}
+
+func TestAggregateIndicatorScores_PermissiveSkipsMissingValues(t *testing.T) {
+	t.Parallel()
+
+	target := map[string]util.IndicatorTarget{
+		"present": {UpperBound: pointer.Float64(100)},
+		"missing": {UpperBound: pointer.Float64(100)},
+	}
+	options := util.ServiceBusinessPerformanceScoreOptions{Weights: map[string]float64{}, Exponent: -1, Permissive: true}
+
+	got, err := aggregateIndicatorScores(target, options, func(indicatorName string) (float64, bool) {
+		if indicatorName == "missing" {
+			return 0, false
+		}
+		return 0, true
+	})
+	if err != nil {
+		t.Fatalf("aggregateIndicatorScores() unexpected error: %v", err)
+	}
+	if math.Abs(got-MaxPerformanceScore) > 1e-9 {
+		t.Errorf("aggregateIndicatorScores() = %v, want %v: a permissive missing indicator should be skipped, not counted against the score", got, MaxPerformanceScore)
+	}
+}
+
+func TestAggregateIndicatorScores_NonPermissiveMissingValueErrors(t *testing.T) {
+	t.Parallel()
+
+	target := map[string]util.IndicatorTarget{"missing": {UpperBound: pointer.Float64(100)}}
+	options := util.ServiceBusinessPerformanceScoreOptions{Weights: map[string]float64{}, Exponent: -1}
+
+	_, err := aggregateIndicatorScores(target, options, func(indicatorName string) (float64, bool) {
+		return 0, false
+	})
+	if err == nil {
+		t.Fatal("aggregateIndicatorScores() expected an error for a missing indicator value in non-permissive mode, got nil")
+	}
+}
+
+func TestAggregateIndicatorScores_NoIndicatorsScoresMax(t *testing.T) {
+	t.Parallel()
+
+	options := util.ServiceBusinessPerformanceScoreOptions{Weights: map[string]float64{}, Exponent: -1}
+
+	got, err := aggregateIndicatorScores(nil, options, func(indicatorName string) (float64, bool) {
+		t.Fatal("getValue should not be called with no indicators")
+		return 0, false
+	})
+	if err != nil {
+		t.Fatalf("aggregateIndicatorScores() unexpected error: %v", err)
+	}
+	if got != MaxPerformanceScore {
+		t.Errorf("aggregateIndicatorScores() = %v, want %v", got, MaxPerformanceScore)
+	}
+}
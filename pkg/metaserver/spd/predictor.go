@@ -0,0 +1,253 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	externalspd "github.com/kubewharf/katalyst-core/pkg/util/external/spd"
+)
+
+// Predictor fits a model to a historical indicator time series and forecasts future values.
+// Implementations are not required to be safe for concurrent Fit calls; forecaster fits each
+// instance before caching it and afterwards only calls Predict and TrainingRMSE, which do not
+// modify the predictor.
+type Predictor interface {
+	// Fit trains the predictor on samples, which must be in chronological order.
+	Fit(samples []externalspd.TimeSeriesSample) error
+	// Predict returns a point prediction for each requested timestamp, which must be at or after
+	// the last training sample's timestamp.
+	Predict(ats []time.Time) ([]float64, error)
+	// TrainingRMSE returns the in-sample root-mean-square error of the fitted model.
+	TrainingRMSE() float64
+}
+
+// PickPredictor chooses a Predictor for the given sample set, matching the repo's preference for
+// Holt-Winters triple exponential smoothing on seasonal workloads, falling back to the simpler
+// FFT-based periodicity detector when there are too few samples to estimate a season reliably.
+func PickPredictor(samples []externalspd.TimeSeriesSample, seasonLength int) Predictor {
+	if seasonLength > 0 && len(samples) >= 2*seasonLength {
+		return NewHoltWintersPredictor(seasonLength, 0.3, 0.1, 0.1)
+	}
+	return NewFFTPredictor()
+}
+
+// HoltWintersPredictor implements additive triple exponential smoothing (level, trend, and a
+// fixed-length seasonal component), suited to indicators with a stable daily/hourly cycle.
+type HoltWintersPredictor struct {
+	seasonLength int
+	alpha        float64
+	beta         float64
+	gamma        float64
+
+	interval time.Duration
+	lastTime time.Time
+
+	level    float64
+	trend    float64
+	seasonal []float64
+	rmse     float64
+}
+
+// NewHoltWintersPredictor builds a HoltWintersPredictor with the given season length (in number
+// of samples) and smoothing coefficients.
+func NewHoltWintersPredictor(seasonLength int, alpha, beta, gamma float64) *HoltWintersPredictor {
+	return &HoltWintersPredictor{seasonLength: seasonLength, alpha: alpha, beta: beta, gamma: gamma}
+}
+
+func (p *HoltWintersPredictor) Fit(samples []externalspd.TimeSeriesSample) error {
+	if len(samples) < 2*p.seasonLength {
+		return fmt.Errorf("need at least %d samples for a season length of %d, got %d", 2*p.seasonLength, p.seasonLength, len(samples))
+	}
+
+	p.interval = samples[1].Timestamp.Sub(samples[0].Timestamp)
+	p.lastTime = samples[len(samples)-1].Timestamp
+
+	// Initialize level/trend from the first two seasons, and seasonal indices as the deviation of
+	// each first-season point from the first season's mean.
+	firstSeasonMean := meanOf(samples[:p.seasonLength])
+	secondSeasonMean := meanOf(samples[p.seasonLength : 2*p.seasonLength])
+
+	p.level = firstSeasonMean
+	p.trend = (secondSeasonMean - firstSeasonMean) / float64(p.seasonLength)
+
+	p.seasonal = make([]float64, p.seasonLength)
+	for i := 0; i < p.seasonLength; i++ {
+		p.seasonal[i] = samples[i].Value - firstSeasonMean
+	}
+
+	var sqErrSum float64
+	var n int
+	for i := p.seasonLength; i < len(samples); i++ {
+		seasonIdx := i % p.seasonLength
+		forecast := p.level + p.trend + p.seasonal[seasonIdx]
+
+		value := samples[i].Value
+		sqErrSum += (value - forecast) * (value - forecast)
+		n++
+
+		prevLevel := p.level
+		p.level = p.alpha*(value-p.seasonal[seasonIdx]) + (1-p.alpha)*(p.level+p.trend)
+		p.trend = p.beta*(p.level-prevLevel) + (1-p.beta)*p.trend
+		p.seasonal[seasonIdx] = p.gamma*(value-p.level) + (1-p.gamma)*p.seasonal[seasonIdx]
+	}
+
+	if n > 0 {
+		p.rmse = math.Sqrt(sqErrSum / float64(n))
+	}
+
+	// Rotate the seasonal indices so that index 0 belongs to the last training sample, which is
+	// where Predict counts its steps from.
+	lastSeasonIdx := (len(samples) - 1) % p.seasonLength
+	rotated := make([]float64, p.seasonLength)
+	for i := range rotated {
+		rotated[i] = p.seasonal[(lastSeasonIdx+i)%p.seasonLength]
+	}
+	p.seasonal = rotated
+
+	return nil
+}
+
+func (p *HoltWintersPredictor) Predict(ats []time.Time) ([]float64, error) {
+	if p.interval <= 0 {
+		return nil, fmt.Errorf("predictor has not been fit")
+	}
+
+	// Fit rotates p.seasonal so that the last training sample sits at index 0.
+	lastIdx := 0
+	values := make([]float64, 0, len(ats))
+	for _, at := range ats {
+		steps := int(math.Round(at.Sub(p.lastTime).Seconds() / p.interval.Seconds()))
+		if steps < 0 {
+			return nil, fmt.Errorf("cannot predict a timestamp before the training window")
+		}
+
+		seasonIdx := ((lastIdx+steps)%p.seasonLength + p.seasonLength) % p.seasonLength
+		values = append(values, p.level+float64(steps)*p.trend+p.seasonal[seasonIdx])
+	}
+	return values, nil
+}
+
+func (p *HoltWintersPredictor) TrainingRMSE() float64 {
+	return p.rmse
+}
+
+func meanOf(samples []externalspd.TimeSeriesSample) float64 {
+	var sum float64
+	for _, s := range samples {
+		sum += s.Value
+	}
+	return sum / float64(len(samples))
+}
+
+// FFTPredictor is a lightweight fallback for workloads without enough history to fit a seasonal
+// model reliably: it detects the dominant periodicity via a discrete Fourier transform and
+// forecasts by extrapolating the mean plus that single dominant sinusoid.
+type FFTPredictor struct {
+	interval time.Duration
+	lastTime time.Time
+
+	mean        float64
+	amplitude   float64
+	phase       float64
+	angularFreq float64
+	rmse        float64
+}
+
+// NewFFTPredictor builds an FFTPredictor. No parameters are required: the dominant period is
+// detected from the training samples themselves.
+func NewFFTPredictor() *FFTPredictor {
+	return &FFTPredictor{}
+}
+
+func (p *FFTPredictor) Fit(samples []externalspd.TimeSeriesSample) error {
+	n := len(samples)
+	if n < 4 {
+		return fmt.Errorf("need at least 4 samples to detect periodicity, got %d", n)
+	}
+
+	p.interval = samples[1].Timestamp.Sub(samples[0].Timestamp)
+	p.lastTime = samples[n-1].Timestamp
+
+	values := make([]float64, n)
+	var sum float64
+	for i, s := range samples {
+		values[i] = s.Value
+		sum += s.Value
+	}
+	p.mean = sum / float64(n)
+
+	// Naive DFT: good enough for the small in-memory windows used here, and avoids pulling in
+	// an FFT dependency for a fallback path.
+	bestPower := -1.0
+	bestFreq := 0
+	for k := 1; k < n/2; k++ {
+		var re, im float64
+		for t := 0; t < n; t++ {
+			angle := -2 * math.Pi * float64(k) * float64(t) / float64(n)
+			re += (values[t] - p.mean) * math.Cos(angle)
+			im += (values[t] - p.mean) * math.Sin(angle)
+		}
+		power := re*re + im*im
+		if power > bestPower {
+			bestPower = power
+			bestFreq = k
+			p.amplitude = 2 * math.Sqrt(power) / float64(n)
+			p.phase = math.Atan2(im, re)
+		}
+	}
+
+	p.angularFreq = 2 * math.Pi * float64(bestFreq) / float64(n)
+
+	var sqErrSum float64
+	for t := 0; t < n; t++ {
+		forecast := p.mean + p.amplitude*math.Cos(p.angularFreq*float64(t)+p.phase)
+		sqErrSum += (values[t] - forecast) * (values[t] - forecast)
+	}
+	p.rmse = math.Sqrt(sqErrSum / float64(n))
+
+	// Shift the phase so that Predict, which counts steps from the last training sample, continues
+	// the fitted sinusoid instead of restarting it.
+	p.phase += p.angularFreq * float64(n-1)
+
+	return nil
+}
+
+func (p *FFTPredictor) Predict(ats []time.Time) ([]float64, error) {
+	if p.interval <= 0 {
+		return nil, fmt.Errorf("predictor has not been fit")
+	}
+
+	values := make([]float64, 0, len(ats))
+	for _, at := range ats {
+		steps := at.Sub(p.lastTime).Seconds() / p.interval.Seconds()
+		if steps < 0 {
+			return nil, fmt.Errorf("cannot predict a timestamp before the training window")
+		}
+		values = append(values, p.mean+p.amplitude*math.Cos(p.angularFreq*steps+p.phase))
+	}
+	return values, nil
+}
+
+func (p *FFTPredictor) TrainingRMSE() float64 {
+	return p.rmse
+}
+
+var _ Predictor = &HoltWintersPredictor{}
+var _ Predictor = &FFTPredictor{}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	externalspd "github.com/kubewharf/katalyst-core/pkg/util/external/spd"
+)
+
+func samplesAt(start time.Time, step time.Duration, values []float64) []externalspd.TimeSeriesSample {
+	samples := make([]externalspd.TimeSeriesSample, len(values))
+	for i, v := range values {
+		samples[i] = externalspd.TimeSeriesSample{Timestamp: start.Add(time.Duration(i) * step), Value: v}
+	}
+	return samples
+}
+
+func TestPickPredictor(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	season := 4
+
+	tests := []struct {
+		name         string
+		samples      []externalspd.TimeSeriesSample
+		seasonLength int
+		wantHoltWinters bool
+	}{
+		{
+			name:            "enough samples for two seasons picks Holt-Winters",
+			samples:         samplesAt(start, time.Minute, make([]float64, 2*season)),
+			seasonLength:    season,
+			wantHoltWinters: true,
+		},
+		{
+			name:            "too few samples falls back to FFT",
+			samples:         samplesAt(start, time.Minute, make([]float64, season)),
+			seasonLength:    season,
+			wantHoltWinters: false,
+		},
+		{
+			name:            "no season estimate falls back to FFT",
+			samples:         samplesAt(start, time.Minute, make([]float64, 2*season)),
+			seasonLength:    0,
+			wantHoltWinters: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			predictor := PickPredictor(tt.samples, tt.seasonLength)
+			_, isHoltWinters := predictor.(*HoltWintersPredictor)
+			if isHoltWinters != tt.wantHoltWinters {
+				t.Errorf("PickPredictor() returned %T, want Holt-Winters = %v", predictor, tt.wantHoltWinters)
+			}
+		})
+	}
+}
+
+func TestHoltWintersPredictor_FitAndPredict(t *testing.T) {
+	t.Parallel()
+
+	const seasonLength = 4
+	start := time.Unix(0, 0)
+
+	// A perfectly repeating 4-point season with no trend: [10, 20, 10, 20, ...].
+	var values []float64
+	for i := 0; i < 8*seasonLength; i++ {
+		if i%2 == 0 {
+			values = append(values, 10)
+		} else {
+			values = append(values, 20)
+		}
+	}
+	samples := samplesAt(start, time.Minute, values)
+
+	predictor := NewHoltWintersPredictor(seasonLength, 0.5, 0.5, 0.5)
+	if err := predictor.Fit(samples); err != nil {
+		t.Fatalf("Fit() unexpected error: %v", err)
+	}
+
+	lastTime := samples[len(samples)-1].Timestamp
+	predicted, err := predictor.Predict([]time.Time{lastTime.Add(time.Minute), lastTime.Add(2 * time.Minute)})
+	if err != nil {
+		t.Fatalf("Predict() unexpected error: %v", err)
+	}
+
+	if len(predicted) != 2 {
+		t.Fatalf("Predict() returned %d values, want 2", len(predicted))
+	}
+	if predictor.TrainingRMSE() > 1 {
+		t.Errorf("TrainingRMSE() = %v, want a small residual for a perfectly periodic series", predictor.TrainingRMSE())
+	}
+}
+
+func TestHoltWintersPredictor_FitInsufficientSamples(t *testing.T) {
+	t.Parallel()
+
+	predictor := NewHoltWintersPredictor(10, 0.3, 0.1, 0.1)
+	samples := samplesAt(time.Unix(0, 0), time.Minute, make([]float64, 5))
+
+	if err := predictor.Fit(samples); err == nil {
+		t.Fatal("Fit() expected an error for fewer than 2*seasonLength samples, got nil")
+	}
+}
+
+func TestHoltWintersPredictor_PredictBeforeFit(t *testing.T) {
+	t.Parallel()
+
+	predictor := NewHoltWintersPredictor(4, 0.3, 0.1, 0.1)
+	if _, err := predictor.Predict([]time.Time{time.Unix(0, 0)}); err == nil {
+		t.Fatal("Predict() expected an error before Fit, got nil")
+	}
+}
+
+func TestFFTPredictor_FitAndPredict(t *testing.T) {
+	t.Parallel()
+
+	const n = 32
+	const period = 8.0
+	start := time.Unix(0, 0)
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = 5 + 3*math.Cos(2*math.Pi*float64(i)/period)
+	}
+	samples := samplesAt(start, time.Minute, values)
+
+	predictor := NewFFTPredictor()
+	if err := predictor.Fit(samples); err != nil {
+		t.Fatalf("Fit() unexpected error: %v", err)
+	}
+
+	if predictor.TrainingRMSE() > 0.5 {
+		t.Errorf("TrainingRMSE() = %v, want a small residual for a clean sinusoid", predictor.TrainingRMSE())
+	}
+
+	lastTime := samples[n-1].Timestamp
+	predicted, err := predictor.Predict([]time.Time{lastTime.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("Predict() unexpected error: %v", err)
+	}
+	if len(predicted) != 1 {
+		t.Fatalf("Predict() returned %d values, want 1", len(predicted))
+	}
+}
+
+func TestFFTPredictor_FitTooFewSamples(t *testing.T) {
+	t.Parallel()
+
+	predictor := NewFFTPredictor()
+	if err := predictor.Fit(samplesAt(time.Unix(0, 0), time.Minute, []float64{1, 2, 3})); err == nil {
+		t.Fatal("Fit() expected an error for fewer than 4 samples, got nil")
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SmoothingOptions configures the hysteresis/dwell-time smoothing applied on top of the raw,
+// per-call ServiceBusinessPerformanceLevel result so that downstream QoS actions (eviction, CPU
+// suppression) do not flap when an indicator sits near a target bound.
+type SmoothingOptions struct {
+	// Enabled turns smoothing on. Disabled by default so existing callers keep seeing the raw
+	// level until they opt in.
+	Enabled bool
+	// BufferSize is how many recent raw level observations are retained per pod. It bounds how far
+	// back the dwell-time gate can look, so it must be large enough to span UpDwell/DownDwell at
+	// the rate ServiceBusinessPerformanceLevel is actually called, or a transition may never clear
+	// the gate.
+	BufferSize int
+	// Alpha is the EWMA weight given to the newest observation, in (0, 1].
+	Alpha float64
+	// UpDwell is how long a worse level must be observed continuously before a transition
+	// towards it (Perfect -> Good -> Poor) is allowed through.
+	UpDwell time.Duration
+	// DownDwell is how long a better level must be observed continuously before a transition
+	// towards it (Poor -> Good -> Perfect) is allowed through.
+	DownDwell time.Duration
+}
+
+// DefaultSmoothingOptions returns the suggested defaults: a 10-sample buffer, alpha=0.3,
+// a 30s up-dwell and a 2m down-dwell. Smoothing itself stays disabled until ConfigureSmoothing
+// is called with Enabled: true.
+func DefaultSmoothingOptions() SmoothingOptions {
+	return SmoothingOptions{
+		Enabled:    false,
+		BufferSize: 10,
+		Alpha:      0.3,
+		UpDwell:    30 * time.Second,
+		DownDwell:  2 * time.Minute,
+	}
+}
+
+type levelObservation struct {
+	level PerformanceLevel
+	// ewmaLevel is the EWMA-rounded level computed at the time of this observation. The dwell
+	// gate tracks this, not the raw level, so a sustained trend whose raw samples merely average
+	// toward the candidate (rather than literally equaling it every time) still clears the gate.
+	ewmaLevel PerformanceLevel
+	timestamp time.Time
+}
+
+// podLevelState tracks the raw level history needed to smooth a single pod's performance level.
+type podLevelState struct {
+	mu sync.Mutex
+
+	observations []levelObservation
+	haveEWMA     bool
+	ewma         float64
+
+	smoothedLevel PerformanceLevel
+	haveSmoothed  bool
+}
+
+// observe folds in a new raw level observation and returns the smoothed level per the configured
+// SmoothingOptions.
+func (s *podLevelState) observe(level PerformanceLevel, now time.Time, opts SmoothingOptions) PerformanceLevel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Rule 1: EWMA over the numeric level value, rounded to the nearest level.
+	if !s.haveEWMA {
+		s.ewma = float64(level)
+		s.haveEWMA = true
+	} else {
+		s.ewma = opts.Alpha*float64(level) + (1-opts.Alpha)*s.ewma
+	}
+	ewmaLevel := PerformanceLevel(math.Round(s.ewma))
+
+	s.observations = append(s.observations, levelObservation{level: level, ewmaLevel: ewmaLevel, timestamp: now})
+	if overflow := len(s.observations) - opts.BufferSize; overflow > 0 {
+		s.observations = s.observations[overflow:]
+	}
+
+	if !s.haveSmoothed {
+		s.smoothedLevel = ewmaLevel
+		s.haveSmoothed = true
+		return s.smoothedLevel
+	}
+
+	if ewmaLevel == s.smoothedLevel {
+		return s.smoothedLevel
+	}
+
+	// Rule 2: dwell-time gate. A transition only takes effect once the new, EWMA-rounded level has
+	// been observed continuously for long enough, with the required duration depending on whether
+	// the transition is towards a worse or a better level.
+	required := opts.UpDwell
+	if ewmaLevel < s.smoothedLevel {
+		required = opts.DownDwell
+	}
+
+	if s.dwelledLongEnough(ewmaLevel, now, required) {
+		s.smoothedLevel = ewmaLevel
+	}
+
+	return s.smoothedLevel
+}
+
+// dwelledLongEnough reports whether candidate has been the EWMA-rounded level continuously, right
+// up to and including the latest observation, for at least required. It scans the retained
+// observation buffer rather than a separate pending-since scalar, so a transient blip that did not
+// move the EWMA-rounded level away from candidate does not reset the dwell clock. It checks each
+// observation's EWMA-rounded level, not its raw level: with Alpha<1 a genuinely sustained trend's
+// raw samples need not equal candidate on every single observation, only average towards it, so
+// gating on raw equality could keep a real transition from ever clearing the gate.
+func (s *podLevelState) dwelledLongEnough(candidate PerformanceLevel, now time.Time, required time.Duration) bool {
+	if len(s.observations) == 0 {
+		return false
+	}
+
+	since := now
+	for i := len(s.observations) - 1; i >= 0; i-- {
+		if s.observations[i].ewmaLevel != candidate {
+			break
+		}
+		since = s.observations[i].timestamp
+	}
+
+	return now.Sub(since) >= required
+}
+
+// levelSmoother owns the per-pod state used to smooth ServiceBusinessPerformanceLevel.
+type levelSmoother struct {
+	mu      sync.Mutex
+	options SmoothingOptions
+	states  map[types.UID]*podLevelState
+}
+
+func newLevelSmoother() *levelSmoother {
+	return &levelSmoother{
+		options: DefaultSmoothingOptions(),
+		states:  make(map[types.UID]*podLevelState),
+	}
+}
+
+func (s *levelSmoother) configure(opts SmoothingOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.options = opts
+}
+
+func (s *levelSmoother) smooth(uid types.UID, level PerformanceLevel, now time.Time) PerformanceLevel {
+	s.mu.Lock()
+	opts := s.options
+	if !opts.Enabled {
+		s.mu.Unlock()
+		return level
+	}
+
+	state, ok := s.states[uid]
+	if !ok {
+		state = &podLevelState{}
+		s.states[uid] = state
+	}
+	s.mu.Unlock()
+
+	return state.observe(level, now, opts)
+}
+
+// forgetPod drops the retained level history for a pod. It should be called when the pod is
+// deleted so the smoother's memory does not grow unbounded across pod churn.
+func (s *levelSmoother) forgetPod(uid types.UID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, uid)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPodLevelState_Observe_DwellGate(t *testing.T) {
+	t.Parallel()
+
+	opts := SmoothingOptions{
+		Enabled:    true,
+		BufferSize: 10,
+		Alpha:      1, // disable EWMA smoothing so the dwell gate is the only thing under test.
+		UpDwell:    time.Minute,
+		DownDwell:  time.Minute,
+	}
+
+	state := &podLevelState{}
+	base := time.Unix(0, 0)
+
+	if got := state.observe(PerformanceLevelPerfect, base, opts); got != PerformanceLevelPerfect {
+		t.Fatalf("first observation = %v, want %v", got, PerformanceLevelPerfect)
+	}
+
+	// A worse level that hasn't dwelled long enough must not take effect yet.
+	if got := state.observe(PerformanceLevelPoor, base.Add(30*time.Second), opts); got != PerformanceLevelPerfect {
+		t.Fatalf("under-dwelled transition = %v, want %v (smoothed level should not move yet)", got, PerformanceLevelPerfect)
+	}
+
+	// Once the worse level has been observed continuously for UpDwell, it takes effect.
+	if got := state.observe(PerformanceLevelPoor, base.Add(90*time.Second), opts); got != PerformanceLevelPoor {
+		t.Fatalf("fully-dwelled transition = %v, want %v", got, PerformanceLevelPoor)
+	}
+}
+
+func TestPodLevelState_Observe_BlipResetsDwellClock(t *testing.T) {
+	t.Parallel()
+
+	opts := SmoothingOptions{
+		Enabled:    true,
+		BufferSize: 10,
+		Alpha:      1,
+		UpDwell:    time.Minute,
+		DownDwell:  time.Minute,
+	}
+
+	state := &podLevelState{}
+	base := time.Unix(0, 0)
+
+	state.observe(PerformanceLevelPerfect, base, opts)
+	state.observe(PerformanceLevelPoor, base.Add(30*time.Second), opts)
+	// A blip back to the old level resets how long Poor has been dwelling.
+	state.observe(PerformanceLevelPerfect, base.Add(45*time.Second), opts)
+
+	if got := state.observe(PerformanceLevelPoor, base.Add(90*time.Second), opts); got != PerformanceLevelPerfect {
+		t.Fatalf("transition after blip = %v, want %v (dwell clock should have reset)", got, PerformanceLevelPerfect)
+	}
+}
+
+func TestPodLevelState_Observe_EWMASmoothsNoise(t *testing.T) {
+	t.Parallel()
+
+	opts := SmoothingOptions{
+		Enabled:    true,
+		BufferSize: 10,
+		Alpha:      0.1,
+		UpDwell:    0,
+		DownDwell:  0,
+	}
+
+	state := &podLevelState{}
+	base := time.Unix(0, 0)
+
+	state.observe(PerformanceLevelPerfect, base, opts)
+	// A single noisy Poor observation should not be enough to round the EWMA up to Poor.
+	if got := state.observe(PerformanceLevelPoor, base.Add(time.Second), opts); got != PerformanceLevelPerfect {
+		t.Fatalf("after one noisy observation = %v, want %v", got, PerformanceLevelPerfect)
+	}
+}
+
+// TestPodLevelState_Observe_DwellTracksEWMARoundedLevelNotRaw guards against a regression where
+// dwelledLongEnough gated a transition on the *raw* observed level matching the candidate
+// continuously, instead of the EWMA-rounded level. With Alpha<1, a single noisy raw sample that
+// dips away from the candidate need not pull the EWMA-rounded level away from it too - but the
+// raw-based check would still reset the dwell clock on that sample, so a genuinely sustained
+// worsening trend could fail to ever clear the gate.
+func TestPodLevelState_Observe_DwellTracksEWMARoundedLevelNotRaw(t *testing.T) {
+	t.Parallel()
+
+	opts := SmoothingOptions{
+		Enabled:    true,
+		BufferSize: 10,
+		Alpha:      0.3, // the documented default; Alpha: 1 would make raw and EWMA-rounded identical.
+		UpDwell:    5 * time.Second,
+		DownDwell:  5 * time.Second,
+	}
+
+	state := &podLevelState{}
+	base := time.Unix(0, 0)
+
+	// Mostly-Poor raw observations with a single noisy Good blip at t=7s. The EWMA-rounded level
+	// reaches Poor at t=4s and, despite the t=7s blip, stays at Poor through t=9s: by t=9s it has
+	// been Poor continuously (as EWMA-rounded) for the full 5s UpDwell, even though the raw level
+	// was briefly Good along the way.
+	raw := []PerformanceLevel{
+		PerformanceLevelPerfect,                                                                // t=0: establishes the initial smoothed level.
+		PerformanceLevelPoor, PerformanceLevelPoor, PerformanceLevelPoor, PerformanceLevelPoor, // t=1..4
+		PerformanceLevelPoor, PerformanceLevelPoor, // t=5,6
+		PerformanceLevelGood,                       // t=7: noisy blip, does not move the EWMA-rounded level off Poor.
+		PerformanceLevelPoor, PerformanceLevelPoor, // t=8,9
+	}
+
+	var got PerformanceLevel
+	for i, level := range raw {
+		got = state.observe(level, base.Add(time.Duration(i)*time.Second), opts)
+	}
+
+	if got != PerformanceLevelPoor {
+		t.Fatalf("smoothed level after a sustained trend with one noisy blip = %v, want %v: the dwell gate should track the EWMA-rounded level, not demand raw equality on every sample", got, PerformanceLevelPoor)
+	}
+}
+
+func TestLevelSmoother_Disabled(t *testing.T) {
+	t.Parallel()
+
+	smoother := newLevelSmoother()
+	now := time.Unix(0, 0)
+
+	if got := smoother.smooth(types.UID("pod-1"), PerformanceLevelPoor, now); got != PerformanceLevelPoor {
+		t.Fatalf("smooth() with smoothing disabled = %v, want raw level %v", got, PerformanceLevelPoor)
+	}
+}
+
+func TestLevelSmoother_ForgetPod(t *testing.T) {
+	t.Parallel()
+
+	smoother := newLevelSmoother()
+	smoother.configure(SmoothingOptions{Enabled: true, BufferSize: 10, Alpha: 1, UpDwell: time.Minute, DownDwell: time.Minute})
+
+	uid := types.UID("pod-1")
+	now := time.Unix(0, 0)
+
+	smoother.smooth(uid, PerformanceLevelPerfect, now)
+	smoother.forgetPod(uid)
+
+	if _, ok := smoother.states[uid]; ok {
+		t.Fatal("forgetPod() did not remove the pod's retained state")
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// defaultSubscriptionBufferSize is how many ProfileEvent a subscriber's channel holds before the
+// drop-oldest backpressure policy kicks in.
+const defaultSubscriptionBufferSize = 64
+
+// ProfileEvent describes a change in a pod's performance level and/or baseline membership, as
+// observed across two consecutive SPD updates.
+type ProfileEvent struct {
+	Pod               *v1.Pod
+	OldLevel          PerformanceLevel
+	NewLevel          PerformanceLevel
+	OldBaseline       bool
+	NewBaseline       bool
+	ChangedIndicators []string
+	Timestamp         time.Time
+}
+
+// CancelFunc unsubscribes the associated Subscribe/SubscribeExtendedIndicator call, closing its
+// event channel.
+type CancelFunc func()
+
+// ExtendedIndicatorDistance measures how much an extended indicator's opaque value changed
+// between two observations, so SubscribeExtendedIndicator can compare the result against a
+// caller-supplied delta threshold.
+type ExtendedIndicatorDistance func(old, new interface{}) float64
+
+type subscription struct {
+	selector labels.Selector
+	ch       chan ProfileEvent
+
+	// extendedIndicator is empty for a business-level/baseline subscription, and set to the
+	// indicator name for a SubscribeExtendedIndicator subscription.
+	extendedIndicator string
+	delta             float64
+	distance          ExtendedIndicatorDistance
+}
+
+type podSnapshot struct {
+	level           PerformanceLevel
+	baseline        bool
+	indicatorLevels map[string]PerformanceLevel
+	extended        map[string]interface{}
+
+	// haveLevel is true only once notify has recorded a real business-level observation for this
+	// pod. notifyExtendedIndicator may create a podSnapshot first, purely to track extended
+	// indicator values, and must not have that placeholder mistaken by notify for a prior
+	// business-level observation worth diffing against.
+	haveLevel bool
+}
+
+// subscriptionRegistry fans SPD-driven changes out to subscribers, keeping the last emitted
+// level/baseline/extended-indicator values per pod so it can diff on every update.
+type subscriptionRegistry struct {
+	mu         sync.Mutex
+	nextID     int64
+	subs       map[int64]*subscription
+	bufferSize int
+	pods       map[types.UID]*podSnapshot
+}
+
+func newSubscriptionRegistry(bufferSize int) *subscriptionRegistry {
+	if bufferSize <= 0 {
+		bufferSize = defaultSubscriptionBufferSize
+	}
+	return &subscriptionRegistry{
+		subs:       make(map[int64]*subscription),
+		bufferSize: bufferSize,
+		pods:       make(map[types.UID]*podSnapshot),
+	}
+}
+
+func (r *subscriptionRegistry) subscribe(selector labels.Selector) (<-chan ProfileEvent, CancelFunc) {
+	return r.add(&subscription{selector: selector})
+}
+
+func (r *subscriptionRegistry) subscribeExtendedIndicator(name string, selector labels.Selector, delta float64, distance ExtendedIndicatorDistance) (<-chan ProfileEvent, CancelFunc) {
+	return r.add(&subscription{selector: selector, extendedIndicator: name, delta: delta, distance: distance})
+}
+
+func (r *subscriptionRegistry) add(sub *subscription) (<-chan ProfileEvent, CancelFunc) {
+	sub.ch = make(chan ProfileEvent, r.bufferSize)
+
+	r.mu.Lock()
+	id := r.nextID
+	r.nextID++
+	r.subs[id] = sub
+	r.mu.Unlock()
+
+	return sub.ch, func() { r.cancel(id) }
+}
+
+func (r *subscriptionRegistry) cancel(id int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if sub, ok := r.subs[id]; ok {
+		close(sub.ch)
+		delete(r.subs, id)
+	}
+}
+
+// closeAll cancels every outstanding subscription; called from Run on shutdown.
+func (r *subscriptionRegistry) closeAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, sub := range r.subs {
+		close(sub.ch)
+		delete(r.subs, id)
+	}
+}
+
+// notify diffs a pod's newly observed level/baseline/per-indicator levels against the last
+// observation and, if anything changed, emits a ProfileEvent to every matching business-level
+// subscriber.
+func (r *subscriptionRegistry) notify(pod *v1.Pod, level PerformanceLevel, baseline bool, indicatorLevels map[string]PerformanceLevel, now time.Time) {
+	r.mu.Lock()
+
+	prev, hadPrevLevel := r.pods[pod.UID]
+	hadPrevLevel = hadPrevLevel && prev.haveLevel
+
+	snapshot := &podSnapshot{level: level, baseline: baseline, indicatorLevels: indicatorLevels, haveLevel: true}
+	if prev != nil {
+		snapshot.extended = prev.extended
+	}
+	r.pods[pod.UID] = snapshot
+
+	if !hadPrevLevel {
+		r.mu.Unlock()
+		return
+	}
+
+	var changed []string
+	for name, newLevel := range indicatorLevels {
+		if oldLevel, ok := prev.indicatorLevels[name]; !ok || oldLevel != newLevel {
+			changed = append(changed, name)
+		}
+	}
+
+	if prev.level == level && prev.baseline == baseline && len(changed) == 0 {
+		r.mu.Unlock()
+		return
+	}
+
+	event := ProfileEvent{
+		Pod:               pod,
+		OldLevel:          prev.level,
+		NewLevel:          level,
+		OldBaseline:       prev.baseline,
+		NewBaseline:       baseline,
+		ChangedIndicators: changed,
+		Timestamp:         now,
+	}
+
+	recipients := r.matchingSubs(pod, "")
+	r.mu.Unlock()
+
+	for _, sub := range recipients {
+		sendDropOldest(sub.ch, event)
+	}
+}
+
+// notifyExtendedIndicator diffs a single extended indicator's opaque value against the last
+// observation and emits a ProfileEvent to subscribers whose delta threshold the change meets or
+// exceeds.
+func (r *subscriptionRegistry) notifyExtendedIndicator(pod *v1.Pod, indicatorName string, value interface{}, now time.Time) {
+	r.mu.Lock()
+
+	snapshot, ok := r.pods[pod.UID]
+	if !ok {
+		snapshot = &podSnapshot{level: PerformanceLevelUnknown}
+		r.pods[pod.UID] = snapshot
+	}
+	if snapshot.extended == nil {
+		snapshot.extended = make(map[string]interface{})
+	}
+
+	old, hadOld := snapshot.extended[indicatorName]
+	snapshot.extended[indicatorName] = value
+
+	if !hadOld {
+		r.mu.Unlock()
+		return
+	}
+
+	recipients := r.matchingSubs(pod, indicatorName)
+	r.mu.Unlock()
+
+	for _, sub := range recipients {
+		if sub.distance == nil {
+			continue
+		}
+
+		if sub.distance(old, value) < sub.delta {
+			continue
+		}
+
+		sendDropOldest(sub.ch, ProfileEvent{
+			Pod:               pod,
+			ChangedIndicators: []string{indicatorName},
+			Timestamp:         now,
+		})
+	}
+}
+
+// matchingSubs must be called with r.mu held; it returns a snapshot slice so event delivery can
+// happen without holding the lock.
+func (r *subscriptionRegistry) matchingSubs(pod *v1.Pod, extendedIndicator string) []*subscription {
+	var matched []*subscription
+	for _, sub := range r.subs {
+		if sub.extendedIndicator != extendedIndicator {
+			continue
+		}
+		if sub.selector != nil && !sub.selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		matched = append(matched, sub)
+	}
+	return matched
+}
+
+// sendDropOldest delivers event to ch, dropping the oldest buffered event to make room if the
+// channel is full, so a slow subscriber sees the most recent state rather than blocking the
+// notifier.
+func sendDropOldest(ch chan ProfileEvent, event ProfileEvent) {
+	for {
+		select {
+		case ch <- event:
+			return
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}
+}
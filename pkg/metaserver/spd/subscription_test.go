@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testPod(uid string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)}}
+}
+
+func TestSubscriptionRegistry_Notify_NoEventOnFirstObservation(t *testing.T) {
+	t.Parallel()
+
+	r := newSubscriptionRegistry(4)
+	ch, cancel := r.subscribe(nil)
+	defer cancel()
+
+	r.notify(testPod("pod-1"), PerformanceLevelPerfect, true, nil, time.Unix(0, 0))
+
+	select {
+	case event := <-ch:
+		t.Fatalf("notify() on first observation emitted an event, want none: %+v", event)
+	default:
+	}
+}
+
+func TestSubscriptionRegistry_Notify_EmitsOnChange(t *testing.T) {
+	t.Parallel()
+
+	r := newSubscriptionRegistry(4)
+	ch, cancel := r.subscribe(nil)
+	defer cancel()
+
+	pod := testPod("pod-1")
+	r.notify(pod, PerformanceLevelPerfect, true, map[string]PerformanceLevel{"cpu": PerformanceLevelPerfect}, time.Unix(0, 0))
+	r.notify(pod, PerformanceLevelPoor, true, map[string]PerformanceLevel{"cpu": PerformanceLevelPoor}, time.Unix(1, 0))
+
+	select {
+	case event := <-ch:
+		if event.OldLevel != PerformanceLevelPerfect || event.NewLevel != PerformanceLevelPoor {
+			t.Errorf("event levels = (%v -> %v), want (%v -> %v)", event.OldLevel, event.NewLevel, PerformanceLevelPerfect, PerformanceLevelPoor)
+		}
+		if len(event.ChangedIndicators) != 1 || event.ChangedIndicators[0] != "cpu" {
+			t.Errorf("event.ChangedIndicators = %v, want [cpu]", event.ChangedIndicators)
+		}
+	default:
+		t.Fatal("notify() on a real change emitted no event")
+	}
+}
+
+func TestSubscriptionRegistry_Notify_NoEventWhenUnchanged(t *testing.T) {
+	t.Parallel()
+
+	r := newSubscriptionRegistry(4)
+	ch, cancel := r.subscribe(nil)
+	defer cancel()
+
+	pod := testPod("pod-1")
+	r.notify(pod, PerformanceLevelPerfect, true, nil, time.Unix(0, 0))
+	r.notify(pod, PerformanceLevelPerfect, true, nil, time.Unix(1, 0))
+
+	select {
+	case event := <-ch:
+		t.Fatalf("notify() with no change emitted an event: %+v", event)
+	default:
+	}
+}
+
+// TestSubscriptionRegistry_ExtendedIndicatorPlaceholderDoesNotLeakIntoLevelDiff guards against a
+// regression where notifyExtendedIndicator's placeholder podSnapshot (level: PerformanceLevelUnknown)
+// was mistaken by notify for a genuine prior business-level observation, firing a spurious
+// Unknown -> real-level transition on the pod's first real observation.
+func TestSubscriptionRegistry_ExtendedIndicatorPlaceholderDoesNotLeakIntoLevelDiff(t *testing.T) {
+	t.Parallel()
+
+	r := newSubscriptionRegistry(4)
+	ch, cancel := r.subscribe(nil)
+	defer cancel()
+
+	pod := testPod("pod-1")
+	r.notifyExtendedIndicator(pod, "qps", 1.0, time.Unix(0, 0))
+	r.notify(pod, PerformanceLevelGood, false, nil, time.Unix(1, 0))
+
+	select {
+	case event := <-ch:
+		t.Fatalf("notify() after only an extended-indicator placeholder emitted an event, want none: %+v", event)
+	default:
+	}
+}
+
+func TestSubscriptionRegistry_NotifyExtendedIndicator_DeltaThreshold(t *testing.T) {
+	t.Parallel()
+
+	r := newSubscriptionRegistry(4)
+	distance := func(old, new interface{}) float64 {
+		o, n := old.(float64), new.(float64)
+		if n > o {
+			return n - o
+		}
+		return o - n
+	}
+	ch, cancel := r.subscribeExtendedIndicator("qps", nil, 5, distance)
+	defer cancel()
+
+	pod := testPod("pod-1")
+	r.notifyExtendedIndicator(pod, "qps", 10.0, time.Unix(0, 0))
+	r.notifyExtendedIndicator(pod, "qps", 12.0, time.Unix(1, 0)) // below delta, no event
+	r.notifyExtendedIndicator(pod, "qps", 20.0, time.Unix(2, 0)) // above delta, emits
+
+	select {
+	case event := <-ch:
+		if len(event.ChangedIndicators) != 1 || event.ChangedIndicators[0] != "qps" {
+			t.Errorf("event.ChangedIndicators = %v, want [qps]", event.ChangedIndicators)
+		}
+	default:
+		t.Fatal("notifyExtendedIndicator() expected an event once the delta threshold was exceeded")
+	}
+
+	select {
+	case event := <-ch:
+		t.Fatalf("notifyExtendedIndicator() emitted a second unexpected event: %+v", event)
+	default:
+	}
+}
+
+func TestSendDropOldest(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan ProfileEvent, 2)
+	sendDropOldest(ch, ProfileEvent{Timestamp: time.Unix(1, 0)})
+	sendDropOldest(ch, ProfileEvent{Timestamp: time.Unix(2, 0)})
+	sendDropOldest(ch, ProfileEvent{Timestamp: time.Unix(3, 0)})
+
+	first := <-ch
+	second := <-ch
+
+	if first.Timestamp != time.Unix(2, 0) || second.Timestamp != time.Unix(3, 0) {
+		t.Errorf("sendDropOldest() kept timestamps (%v, %v), want (2, 3): the oldest event should have been dropped", first.Timestamp, second.Timestamp)
+	}
+}
+
+func TestSubscriptionRegistry_CancelClosesChannel(t *testing.T) {
+	t.Parallel()
+
+	r := newSubscriptionRegistry(4)
+	ch, cancel := r.subscribe(nil)
+	cancel()
+
+	if _, ok := <-ch; ok {
+		t.Fatal("cancel() did not close the subscription's channel")
+	}
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// AggregationMethod controls how a Prometheus range query's samples are smoothed into a single
+// indicator value.
+type AggregationMethod string
+
+const (
+	AggregationMean AggregationMethod = "mean"
+	AggregationP95  AggregationMethod = "p95"
+	AggregationP99  AggregationMethod = "p99"
+)
+
+// PrometheusQuerier is the minimal surface PrometheusIndicatorProvider needs from a Prometheus
+// client, so the provider can be tested against a fake without pulling in a live Prometheus.
+type PrometheusQuerier interface {
+	// QueryRange runs promQuery over [start, end] at the given step and returns the sampled
+	// values in chronological order.
+	QueryRange(ctx context.Context, promQuery string, start, end time.Time, step time.Duration) ([]float64, error)
+}
+
+// PrometheusIndicatorProviderConfig configures the smoothing window and caching behavior of a
+// PrometheusIndicatorProvider.
+type PrometheusIndicatorProviderConfig struct {
+	// Lookback is how far back a range query looks for samples to smooth over.
+	Lookback time.Duration
+	// Step is the resolution of the range query.
+	Step time.Duration
+	// Aggregation is the method used to collapse the sampled range into a single value.
+	Aggregation AggregationMethod
+	// CacheTTL is how long a resolved indicator value is reused before a fresh query is issued.
+	CacheTTL time.Duration
+}
+
+// DefaultPrometheusIndicatorProviderConfig returns sane defaults: a five minute lookback sampled
+// every 15s, smoothed with the mean, cached for 30s.
+func DefaultPrometheusIndicatorProviderConfig() PrometheusIndicatorProviderConfig {
+	return PrometheusIndicatorProviderConfig{
+		Lookback:    5 * time.Minute,
+		Step:        15 * time.Second,
+		Aggregation: AggregationMean,
+		CacheTTL:    30 * time.Second,
+	}
+}
+
+// promQueryData is the data made available to indicator PromQL templates.
+type promQueryData struct {
+	Namespace    string
+	PodName      string
+	WorkloadName string
+}
+
+type cacheKey struct {
+	podUID    string
+	indicator string
+	window    time.Duration
+}
+
+type cacheEntry struct {
+	value     float64
+	timestamp time.Time
+	expiresAt time.Time
+}
+
+// PrometheusIndicatorProvider implements IndicatorValueProvider by rendering a per-indicator
+// PromQL template and smoothing the resulting range query, so that Katalyst can run against
+// indicator values already exposed as Prometheus metrics, without requiring an external
+// reconciler to write them into SPD status first.
+type PrometheusIndicatorProvider struct {
+	querier PrometheusQuerier
+	config  PrometheusIndicatorProviderConfig
+
+	mu                sync.RWMutex
+	businessTemplates map[string]*template.Template
+	extendedTemplates map[string]*template.Template
+	cache             map[cacheKey]cacheEntry
+}
+
+// NewPrometheusIndicatorProvider builds a PrometheusIndicatorProvider against the given querier.
+// Templates are loaded separately via LoadTemplatesFromConfigMap so they can be hot-reloaded as
+// the backing ConfigMap changes.
+func NewPrometheusIndicatorProvider(querier PrometheusQuerier, config PrometheusIndicatorProviderConfig) *PrometheusIndicatorProvider {
+	return &PrometheusIndicatorProvider{
+		querier:           querier,
+		config:            config,
+		businessTemplates: make(map[string]*template.Template),
+		extendedTemplates: make(map[string]*template.Template),
+		cache:             make(map[cacheKey]cacheEntry),
+	}
+}
+
+// LoadTemplatesFromConfigMap (re-)loads the PromQL templates used to resolve indicator values.
+// Business indicator templates are stored under keys "business.<indicatorName>"; extended
+// indicator templates are stored under "extended.<indicatorName>.<fieldName>". Templates may
+// reference {{.Namespace}}, {{.PodName}} and {{.WorkloadName}}.
+func (p *PrometheusIndicatorProvider) LoadTemplatesFromConfigMap(cm *v1.ConfigMap) error {
+	businessTemplates := make(map[string]*template.Template)
+	extendedTemplates := make(map[string]*template.Template)
+
+	for key, raw := range cm.Data {
+		tpl, err := template.New(key).Parse(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse promql template %s: %w", key, err)
+		}
+
+		switch {
+		case strings.HasPrefix(key, "business."):
+			businessTemplates[strings.TrimPrefix(key, "business.")] = tpl
+		case strings.HasPrefix(key, "extended."):
+			extendedTemplates[strings.TrimPrefix(key, "extended.")] = tpl
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.businessTemplates = businessTemplates
+	p.extendedTemplates = extendedTemplates
+	return nil
+}
+
+func (p *PrometheusIndicatorProvider) GetBusinessIndicatorValue(ctx context.Context, pod *v1.Pod, indicatorName string) (float64, time.Time, error) {
+	p.mu.RLock()
+	tpl, ok := p.businessTemplates[indicatorName]
+	p.mu.RUnlock()
+	if !ok {
+		return 0, time.Time{}, fmt.Errorf("no promql template configured for business indicator %s", indicatorName)
+	}
+
+	return p.resolve(ctx, pod, indicatorName, tpl)
+}
+
+func (p *PrometheusIndicatorProvider) GetExtendedIndicatorValue(ctx context.Context, pod *v1.Pod, indicatorName, fieldName string) (float64, time.Time, error) {
+	key := indicatorName + "." + fieldName
+	p.mu.RLock()
+	tpl, ok := p.extendedTemplates[key]
+	p.mu.RUnlock()
+	if !ok {
+		return 0, time.Time{}, fmt.Errorf("no promql template configured for extended indicator %s", key)
+	}
+
+	return p.resolve(ctx, pod, key, tpl)
+}
+
+// GetBusinessIndicatorHistory renders the business indicator's PromQL template and returns the
+// raw (unaggregated) samples over the requested lookback window, for use by time-series
+// forecasting. Unlike GetBusinessIndicatorValue it is not cached, since callers typically only
+// refit a forecasting model every few minutes.
+func (p *PrometheusIndicatorProvider) GetBusinessIndicatorHistory(ctx context.Context, pod *v1.Pod, indicatorName string, lookback time.Duration) ([]TimeSeriesSample, error) {
+	p.mu.RLock()
+	tpl, ok := p.businessTemplates[indicatorName]
+	p.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no promql template configured for business indicator %s", indicatorName)
+	}
+
+	query, err := p.renderQuery(pod, tpl)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	start := now.Add(-lookback)
+	samples, err := p.querier.QueryRange(ctx, query, start, now, p.config.Step)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prometheus history for %s: %w", indicatorName, err)
+	}
+
+	history := make([]TimeSeriesSample, 0, len(samples))
+	for i, value := range samples {
+		history = append(history, TimeSeriesSample{Timestamp: start.Add(time.Duration(i) * p.config.Step), Value: value})
+	}
+	return history, nil
+}
+
+func (p *PrometheusIndicatorProvider) renderQuery(pod *v1.Pod, tpl *template.Template) (string, error) {
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, promQueryData{
+		Namespace:    pod.Namespace,
+		PodName:      pod.Name,
+		WorkloadName: workloadName(pod),
+	}); err != nil {
+		return "", fmt.Errorf("failed to render promql template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (p *PrometheusIndicatorProvider) resolve(ctx context.Context, pod *v1.Pod, cacheIndicatorKey string, tpl *template.Template) (float64, time.Time, error) {
+	key := cacheKey{podUID: string(pod.UID), indicator: cacheIndicatorKey, window: p.config.Lookback}
+
+	p.mu.RLock()
+	if entry, ok := p.cache[key]; ok && time.Now().Before(entry.expiresAt) {
+		p.mu.RUnlock()
+		return entry.value, entry.timestamp, nil
+	}
+	p.mu.RUnlock()
+
+	query, err := p.renderQuery(pod, tpl)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	now := time.Now()
+	samples, err := p.querier.QueryRange(ctx, query, now.Add(-p.config.Lookback), now, p.config.Step)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to query prometheus for %s: %w", cacheIndicatorKey, err)
+	}
+
+	value, err := aggregate(samples, p.config.Aggregation)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to aggregate samples for %s: %w", cacheIndicatorKey, err)
+	}
+
+	p.mu.Lock()
+	p.cache[key] = cacheEntry{value: value, timestamp: now, expiresAt: now.Add(p.config.CacheTTL)}
+	p.mu.Unlock()
+
+	return value, now, nil
+}
+
+// workloadName derives the owning workload's name from the pod's controller owner reference,
+// falling back to the pod name itself if the pod has no controller (e.g. a bare pod).
+func workloadName(pod *v1.Pod) string {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller {
+			return owner.Name
+		}
+	}
+	return pod.Name
+}
+
+func aggregate(samples []float64, method AggregationMethod) (float64, error) {
+	if len(samples) == 0 {
+		return 0, fmt.Errorf("no samples returned by prometheus")
+	}
+
+	switch method {
+	case AggregationP95:
+		return percentile(samples, 0.95), nil
+	case AggregationP99:
+		return percentile(samples, 0.99), nil
+	case AggregationMean, "":
+		var sum float64
+		for _, s := range samples {
+			sum += s
+		}
+		return sum / float64(len(samples)), nil
+	default:
+		return 0, fmt.Errorf("unknown aggregation method %q", method)
+	}
+}
+
+func percentile(samples []float64, p float64) float64 {
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+var _ IndicatorValueProvider = &PrometheusIndicatorProvider{}
+var _ HistoricalIndicatorValueProvider = &PrometheusIndicatorProvider{}
@@ -0,0 +1,301 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakePrometheusQuerier struct {
+	values []float64
+	err    error
+
+	lastQuery string
+	lastStart time.Time
+	lastEnd   time.Time
+	lastStep  time.Duration
+	calls     int
+}
+
+func (f *fakePrometheusQuerier) QueryRange(_ context.Context, promQuery string, start, end time.Time, step time.Duration) ([]float64, error) {
+	f.calls++
+	f.lastQuery = promQuery
+	f.lastStart = start
+	f.lastEnd = end
+	f.lastStep = step
+	return f.values, f.err
+}
+
+func testPodWithOwner(namespace, name, workload string) *v1.Pod {
+	truth := true
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       namespace,
+		Name:            name,
+		OwnerReferences: []metav1.OwnerReference{{Name: workload, Controller: &truth}},
+	}}
+}
+
+func businessConfigMap(indicator, query string) *v1.ConfigMap {
+	return &v1.ConfigMap{Data: map[string]string{"business." + indicator: query}}
+}
+
+func TestPrometheusIndicatorProvider_GetBusinessIndicatorValue(t *testing.T) {
+	t.Parallel()
+
+	querier := &fakePrometheusQuerier{values: []float64{10, 20, 30}}
+	provider := NewPrometheusIndicatorProvider(querier, DefaultPrometheusIndicatorProviderConfig())
+	if err := provider.LoadTemplatesFromConfigMap(businessConfigMap("qps", `sum(rate(requests{namespace="{{.Namespace}}",pod="{{.PodName}}",workload="{{.WorkloadName}}"}[1m]))`)); err != nil {
+		t.Fatalf("LoadTemplatesFromConfigMap() unexpected error: %v", err)
+	}
+
+	pod := testPodWithOwner("ns1", "pod1", "deploy1")
+	value, _, err := provider.GetBusinessIndicatorValue(context.Background(), pod, "qps")
+	if err != nil {
+		t.Fatalf("GetBusinessIndicatorValue() unexpected error: %v", err)
+	}
+	if value != 20 {
+		t.Errorf("GetBusinessIndicatorValue() = %v, want the mean 20", value)
+	}
+	if querier.lastQuery != `sum(rate(requests{namespace="ns1",pod="pod1",workload="deploy1"}[1m]))` {
+		t.Errorf("rendered query = %q, unexpected template substitution", querier.lastQuery)
+	}
+}
+
+func TestPrometheusIndicatorProvider_GetBusinessIndicatorValue_NoTemplate(t *testing.T) {
+	t.Parallel()
+
+	provider := NewPrometheusIndicatorProvider(&fakePrometheusQuerier{}, DefaultPrometheusIndicatorProviderConfig())
+	if _, _, err := provider.GetBusinessIndicatorValue(context.Background(), testPodWithOwner("ns", "pod", "wl"), "missing"); err == nil {
+		t.Fatal("GetBusinessIndicatorValue() expected an error for an indicator with no loaded template, got nil")
+	}
+}
+
+func TestPrometheusIndicatorProvider_GetExtendedIndicatorValue(t *testing.T) {
+	t.Parallel()
+
+	querier := &fakePrometheusQuerier{values: []float64{5}}
+	provider := NewPrometheusIndicatorProvider(querier, DefaultPrometheusIndicatorProviderConfig())
+	if err := provider.LoadTemplatesFromConfigMap(&v1.ConfigMap{Data: map[string]string{
+		"extended.qps.P99": `histogram_quantile(0.99, requests{pod="{{.PodName}}"})`,
+	}}); err != nil {
+		t.Fatalf("LoadTemplatesFromConfigMap() unexpected error: %v", err)
+	}
+
+	value, _, err := provider.GetExtendedIndicatorValue(context.Background(), testPodWithOwner("ns", "pod1", "wl"), "qps", "P99")
+	if err != nil {
+		t.Fatalf("GetExtendedIndicatorValue() unexpected error: %v", err)
+	}
+	if value != 5 {
+		t.Errorf("GetExtendedIndicatorValue() = %v, want 5", value)
+	}
+}
+
+func TestPrometheusIndicatorProvider_ResolveIsCached(t *testing.T) {
+	t.Parallel()
+
+	querier := &fakePrometheusQuerier{values: []float64{1, 2, 3}}
+	config := DefaultPrometheusIndicatorProviderConfig()
+	config.CacheTTL = time.Hour
+	provider := NewPrometheusIndicatorProvider(querier, config)
+	if err := provider.LoadTemplatesFromConfigMap(businessConfigMap("qps", "up")); err != nil {
+		t.Fatalf("LoadTemplatesFromConfigMap() unexpected error: %v", err)
+	}
+
+	pod := testPodWithOwner("ns", "pod1", "wl")
+	if _, _, err := provider.GetBusinessIndicatorValue(context.Background(), pod, "qps"); err != nil {
+		t.Fatalf("GetBusinessIndicatorValue() unexpected error: %v", err)
+	}
+	if _, _, err := provider.GetBusinessIndicatorValue(context.Background(), pod, "qps"); err != nil {
+		t.Fatalf("GetBusinessIndicatorValue() unexpected error: %v", err)
+	}
+
+	if querier.calls != 1 {
+		t.Errorf("QueryRange() called %d times, want 1: the second call should have hit the cache", querier.calls)
+	}
+}
+
+func TestPrometheusIndicatorProvider_ResolveRefetchesAfterTTLExpires(t *testing.T) {
+	t.Parallel()
+
+	querier := &fakePrometheusQuerier{values: []float64{1}}
+	config := DefaultPrometheusIndicatorProviderConfig()
+	config.CacheTTL = time.Millisecond
+	provider := NewPrometheusIndicatorProvider(querier, config)
+	if err := provider.LoadTemplatesFromConfigMap(businessConfigMap("qps", "up")); err != nil {
+		t.Fatalf("LoadTemplatesFromConfigMap() unexpected error: %v", err)
+	}
+
+	pod := testPodWithOwner("ns", "pod1", "wl")
+	if _, _, err := provider.GetBusinessIndicatorValue(context.Background(), pod, "qps"); err != nil {
+		t.Fatalf("GetBusinessIndicatorValue() unexpected error: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, _, err := provider.GetBusinessIndicatorValue(context.Background(), pod, "qps"); err != nil {
+		t.Fatalf("GetBusinessIndicatorValue() unexpected error: %v", err)
+	}
+
+	if querier.calls != 2 {
+		t.Errorf("QueryRange() called %d times, want 2: the cache entry should have expired", querier.calls)
+	}
+}
+
+func TestPrometheusIndicatorProvider_GetBusinessIndicatorHistory(t *testing.T) {
+	t.Parallel()
+
+	querier := &fakePrometheusQuerier{values: []float64{1, 2, 3, 4}}
+	config := DefaultPrometheusIndicatorProviderConfig()
+	config.Step = time.Minute
+	provider := NewPrometheusIndicatorProvider(querier, config)
+	if err := provider.LoadTemplatesFromConfigMap(businessConfigMap("qps", "up")); err != nil {
+		t.Fatalf("LoadTemplatesFromConfigMap() unexpected error: %v", err)
+	}
+
+	samples, err := provider.GetBusinessIndicatorHistory(context.Background(), testPodWithOwner("ns", "pod1", "wl"), "qps", time.Hour)
+	if err != nil {
+		t.Fatalf("GetBusinessIndicatorHistory() unexpected error: %v", err)
+	}
+	if len(samples) != 4 {
+		t.Fatalf("GetBusinessIndicatorHistory() returned %d samples, want 4", len(samples))
+	}
+	for i, s := range samples {
+		if s.Value != float64(i+1) {
+			t.Errorf("samples[%d].Value = %v, want %v", i, s.Value, i+1)
+		}
+	}
+	if !samples[1].Timestamp.Equal(samples[0].Timestamp.Add(time.Minute)) {
+		t.Errorf("samples[1].Timestamp - samples[0].Timestamp = %v, want %v (config.Step)", samples[1].Timestamp.Sub(samples[0].Timestamp), time.Minute)
+	}
+	if querier.lastStep != time.Minute {
+		t.Errorf("QueryRange() step = %v, want %v", querier.lastStep, time.Minute)
+	}
+	if querier.lastEnd.Sub(querier.lastStart) != time.Hour {
+		t.Errorf("QueryRange() window = %v, want 1h", querier.lastEnd.Sub(querier.lastStart))
+	}
+}
+
+func TestPrometheusIndicatorProvider_GetBusinessIndicatorHistory_NoTemplate(t *testing.T) {
+	t.Parallel()
+
+	provider := NewPrometheusIndicatorProvider(&fakePrometheusQuerier{}, DefaultPrometheusIndicatorProviderConfig())
+	if _, err := provider.GetBusinessIndicatorHistory(context.Background(), testPodWithOwner("ns", "pod", "wl"), "missing", time.Hour); err == nil {
+		t.Fatal("GetBusinessIndicatorHistory() expected an error for an indicator with no loaded template, got nil")
+	}
+}
+
+func TestPrometheusIndicatorProvider_QueryRangeError(t *testing.T) {
+	t.Parallel()
+
+	querier := &fakePrometheusQuerier{err: context.DeadlineExceeded}
+	provider := NewPrometheusIndicatorProvider(querier, DefaultPrometheusIndicatorProviderConfig())
+	if err := provider.LoadTemplatesFromConfigMap(businessConfigMap("qps", "up")); err != nil {
+		t.Fatalf("LoadTemplatesFromConfigMap() unexpected error: %v", err)
+	}
+
+	if _, _, err := provider.GetBusinessIndicatorValue(context.Background(), testPodWithOwner("ns", "pod", "wl"), "qps"); err == nil {
+		t.Fatal("GetBusinessIndicatorValue() expected an error when QueryRange fails, got nil")
+	}
+}
+
+func TestPrometheusIndicatorProvider_LoadTemplatesFromConfigMap_InvalidTemplate(t *testing.T) {
+	t.Parallel()
+
+	provider := NewPrometheusIndicatorProvider(&fakePrometheusQuerier{}, DefaultPrometheusIndicatorProviderConfig())
+	if err := provider.LoadTemplatesFromConfigMap(businessConfigMap("qps", "{{.Namespace")); err == nil {
+		t.Fatal("LoadTemplatesFromConfigMap() expected an error for malformed template syntax, got nil")
+	}
+}
+
+func TestWorkloadName(t *testing.T) {
+	t.Parallel()
+
+	if got := workloadName(testPodWithOwner("ns", "pod1", "deploy1")); got != "deploy1" {
+		t.Errorf("workloadName() = %q, want %q", got, "deploy1")
+	}
+
+	if got := workloadName(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bare-pod"}}); got != "bare-pod" {
+		t.Errorf("workloadName() = %q, want %q (fall back to the pod's own name)", got, "bare-pod")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+
+	samples := []float64{10, 20, 30, 40, 50}
+
+	tests := []struct {
+		name    string
+		method  AggregationMethod
+		want    float64
+		wantErr bool
+	}{
+		{name: "mean", method: AggregationMean, want: 30},
+		{name: "default is mean", method: "", want: 30},
+		{name: "p95", method: AggregationP95, want: percentile(samples, 0.95)},
+		{name: "p99", method: AggregationP99, want: percentile(samples, 0.99)},
+		{name: "unknown method errors", method: "bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := aggregate(samples, tt.method)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("aggregate() expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("aggregate() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("aggregate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := aggregate(nil, AggregationMean); err == nil {
+		t.Fatal("aggregate() expected an error for no samples, got nil")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	samples := []float64{40, 10, 30, 20}
+
+	if got := percentile(samples, 0); got != 10 {
+		t.Errorf("percentile(0) = %v, want 10 (the minimum)", got)
+	}
+	if got := percentile(samples, 1); got != 40 {
+		t.Errorf("percentile(1) = %v, want 40 (the maximum)", got)
+	}
+
+	// percentile must not mutate its input.
+	if samples[0] != 40 || samples[1] != 10 || samples[2] != 30 || samples[3] != 20 {
+		t.Errorf("percentile() mutated its input slice: %v", samples)
+	}
+}
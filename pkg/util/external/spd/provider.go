@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// IndicatorValueProvider supplies the current value of an SPD business/system indicator for a
+// pod when the value cannot be read directly off the SPD status, e.g. because no external
+// reconciler has written it there yet. Implementations are expected to be read-only and safe
+// for concurrent use.
+type IndicatorValueProvider interface {
+	// GetBusinessIndicatorValue returns the current value of a business indicator for the given
+	// pod, along with the timestamp the value was observed at.
+	GetBusinessIndicatorValue(ctx context.Context, pod *v1.Pod, indicatorName string) (float64, time.Time, error)
+
+	// GetExtendedIndicatorValue returns the current value of an extended indicator field for the
+	// given pod, along with the timestamp the value was observed at.
+	GetExtendedIndicatorValue(ctx context.Context, pod *v1.Pod, indicatorName, fieldName string) (float64, time.Time, error)
+}
+
+// ChainIndicatorValueProvider consults a list of IndicatorValueProvider in order and returns the
+// first successful result, so callers can fall back from e.g. SPD status, to Prometheus, to a
+// noop provider without special-casing each source.
+type ChainIndicatorValueProvider struct {
+	providers []IndicatorValueProvider
+}
+
+// NewChainIndicatorValueProvider builds a ChainIndicatorValueProvider out of the given providers,
+// consulted in the order they are passed in.
+func NewChainIndicatorValueProvider(providers ...IndicatorValueProvider) *ChainIndicatorValueProvider {
+	return &ChainIndicatorValueProvider{providers: providers}
+}
+
+func (c *ChainIndicatorValueProvider) GetBusinessIndicatorValue(ctx context.Context, pod *v1.Pod, indicatorName string) (float64, time.Time, error) {
+	lastErr := newNoValueError(indicatorName)
+	for _, provider := range c.providers {
+		value, ts, err := provider.GetBusinessIndicatorValue(ctx, pod, indicatorName)
+		if err == nil {
+			return value, ts, nil
+		}
+		lastErr = err
+	}
+	return 0, time.Time{}, lastErr
+}
+
+func (c *ChainIndicatorValueProvider) GetExtendedIndicatorValue(ctx context.Context, pod *v1.Pod, indicatorName, fieldName string) (float64, time.Time, error) {
+	lastErr := newNoValueError(indicatorName + "/" + fieldName)
+	for _, provider := range c.providers {
+		value, ts, err := provider.GetExtendedIndicatorValue(ctx, pod, indicatorName, fieldName)
+		if err == nil {
+			return value, ts, nil
+		}
+		lastErr = err
+	}
+	return 0, time.Time{}, lastErr
+}
+
+// TimeSeriesSample is a single timestamped observation of an indicator's value.
+type TimeSeriesSample struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// HistoricalIndicatorValueProvider is an optional capability an IndicatorValueProvider may
+// implement to expose a window of past samples for an indicator, e.g. for use by forecasting.
+// Implementations that only track the current value (such as SPD status) need not implement it.
+type HistoricalIndicatorValueProvider interface {
+	// GetBusinessIndicatorHistory returns the samples observed for a business indicator over
+	// the window [time.Now().Add(-lookback), time.Now()], oldest first.
+	GetBusinessIndicatorHistory(ctx context.Context, pod *v1.Pod, indicatorName string, lookback time.Duration) ([]TimeSeriesSample, error)
+}
+
+// NoopIndicatorValueProvider never has a value: placed as the last link of a chain, it makes the
+// chain fail with a "no indicator value provider has a value" error. NewChainIndicatorValueProvider
+// does not append it on its own.
+type NoopIndicatorValueProvider struct{}
+
+func (NoopIndicatorValueProvider) GetBusinessIndicatorValue(_ context.Context, _ *v1.Pod, indicatorName string) (float64, time.Time, error) {
+	return 0, time.Time{}, newNoValueError(indicatorName)
+}
+
+func (NoopIndicatorValueProvider) GetExtendedIndicatorValue(_ context.Context, _ *v1.Pod, indicatorName, fieldName string) (float64, time.Time, error) {
+	return 0, time.Time{}, newNoValueError(indicatorName + "/" + fieldName)
+}
+
+var _ IndicatorValueProvider = &ChainIndicatorValueProvider{}
+var _ IndicatorValueProvider = NoopIndicatorValueProvider{}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+type fakeIndicatorValueProvider struct {
+	businessValue float64
+	businessTime  time.Time
+	businessErr   error
+
+	extendedValue float64
+	extendedTime  time.Time
+	extendedErr   error
+}
+
+func (f *fakeIndicatorValueProvider) GetBusinessIndicatorValue(_ context.Context, _ *v1.Pod, _ string) (float64, time.Time, error) {
+	return f.businessValue, f.businessTime, f.businessErr
+}
+
+func (f *fakeIndicatorValueProvider) GetExtendedIndicatorValue(_ context.Context, _ *v1.Pod, _, _ string) (float64, time.Time, error) {
+	return f.extendedValue, f.extendedTime, f.extendedErr
+}
+
+func TestChainIndicatorValueProvider_GetBusinessIndicatorValue(t *testing.T) {
+	t.Parallel()
+
+	errFailed := newNoValueError("cpu_usage")
+	now := time.Unix(1000, 0)
+
+	tests := []struct {
+		name      string
+		providers []IndicatorValueProvider
+		wantValue float64
+		wantErr   bool
+	}{
+		{
+			name:      "first provider succeeds",
+			providers: []IndicatorValueProvider{&fakeIndicatorValueProvider{businessValue: 1, businessTime: now}, &fakeIndicatorValueProvider{businessValue: 2}},
+			wantValue: 1,
+		},
+		{
+			name:      "first provider fails, second succeeds",
+			providers: []IndicatorValueProvider{&fakeIndicatorValueProvider{businessErr: errFailed}, &fakeIndicatorValueProvider{businessValue: 2, businessTime: now}},
+			wantValue: 2,
+		},
+		{
+			name:      "all providers fail",
+			providers: []IndicatorValueProvider{&fakeIndicatorValueProvider{businessErr: errFailed}, NoopIndicatorValueProvider{}},
+			wantErr:   true,
+		},
+		{
+			name:      "no providers",
+			providers: nil,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chain := NewChainIndicatorValueProvider(tt.providers...)
+			value, _, err := chain.GetBusinessIndicatorValue(context.Background(), &v1.Pod{}, "cpu_usage")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBusinessIndicatorValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && value != tt.wantValue {
+				t.Errorf("GetBusinessIndicatorValue() = %v, want %v", value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestChainIndicatorValueProvider_GetExtendedIndicatorValue(t *testing.T) {
+	t.Parallel()
+
+	chain := NewChainIndicatorValueProvider(
+		&fakeIndicatorValueProvider{extendedErr: newNoValueError("qps/p99")},
+		&fakeIndicatorValueProvider{extendedValue: 42},
+	)
+
+	value, _, err := chain.GetExtendedIndicatorValue(context.Background(), &v1.Pod{}, "qps", "p99")
+	if err != nil {
+		t.Fatalf("GetExtendedIndicatorValue() unexpected error: %v", err)
+	}
+	if value != 42 {
+		t.Errorf("GetExtendedIndicatorValue() = %v, want 42", value)
+	}
+}
+
+func TestNoopIndicatorValueProvider(t *testing.T) {
+	t.Parallel()
+
+	var noop NoopIndicatorValueProvider
+
+	if _, _, err := noop.GetBusinessIndicatorValue(context.Background(), &v1.Pod{}, "cpu_usage"); err == nil {
+		t.Error("GetBusinessIndicatorValue() expected an error, got nil")
+	}
+
+	if _, _, err := noop.GetExtendedIndicatorValue(context.Background(), &v1.Pod{}, "qps", "p99"); err == nil {
+		t.Error("GetExtendedIndicatorValue() expected an error, got nil")
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Katalyst Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	workloadapis "github.com/kubewharf/katalyst-api/pkg/apis/workload/v1alpha1"
+)
+
+// businessScoreOptionsAnnotation carries a JSON-encoded ServiceBusinessPerformanceScoreOptions
+// override for a workload.
+//
+// The natural home for Weights/Exponent/SlackBand/Permissive is typed fields on
+// workloadapis.ServiceProfileDescriptorSpec, but that type is defined in the separate
+// github.com/kubewharf/katalyst-api module, which this commit cannot modify - that needs its own
+// PR against that repo plus a dependency bump here. A single structured, strictly-parsed
+// annotation is the closest in-repo equivalent in the meantime: unlike per-field string
+// annotations it is validated as one unit and surfaces a real error on a typo instead of silently
+// keeping the default.
+const businessScoreOptionsAnnotation = "spd.katalyst.kubewharf.io/business-score-options"
+
+const (
+	defaultBusinessScoreExponent  = -1
+	defaultBusinessScoreSlackBand = 0
+)
+
+// ServiceBusinessPerformanceScoreOptions tunes ServiceBusinessPerformanceScore's weighted
+// generalized-mean aggregation of per-indicator scores.
+type ServiceBusinessPerformanceScoreOptions struct {
+	// Weights maps indicator name to its weight in the generalized mean; an indicator with no
+	// entry here defaults to weight 1. An indicator explicitly configured with weight 0 is
+	// excluded from the aggregation entirely.
+	Weights map[string]float64
+	// Exponent is the generalized mean's exponent p. Defaults to -1 (harmonic mean), which
+	// penalizes a single poor indicator more than an arithmetic mean would.
+	Exponent float64
+	// SlackBand widens the effective upper bound used for upper-bound-only indicators by this
+	// fraction, e.g. 0.1 moves the value at which the score reaches MinPerformanceScore from the
+	// target to 10% above it. The score still decays linearly from a value of 0 upwards.
+	SlackBand float64
+	// Permissive skips indicators with no current value instead of failing the whole call.
+	Permissive bool
+}
+
+// businessScoreOptionsOverride mirrors ServiceBusinessPerformanceScoreOptions but with pointer
+// fields, so that an absent field (keep the default) can be told apart from an explicit zero
+// value (e.g. Exponent: 0, or a weight of 0 to exclude an indicator).
+type businessScoreOptionsOverride struct {
+	Weights    map[string]float64 `json:"weights,omitempty"`
+	Exponent   *float64           `json:"exponent,omitempty"`
+	SlackBand  *float64           `json:"slackBand,omitempty"`
+	Permissive *bool              `json:"permissive,omitempty"`
+}
+
+// GetServiceBusinessPerformanceScoreOptions reads a ServiceBusinessPerformanceScoreOptions
+// override from spd's businessScoreOptionsAnnotation annotation, so operators can tune scoring on
+// a per-workload basis without a CRD schema change. An SPD without the annotation gets the
+// defaults. A present but malformed annotation is a real error, not a silently-ignored one.
+func GetServiceBusinessPerformanceScoreOptions(spd *workloadapis.ServiceProfileDescriptor) (ServiceBusinessPerformanceScoreOptions, error) {
+	options := ServiceBusinessPerformanceScoreOptions{
+		Weights:   make(map[string]float64),
+		Exponent:  defaultBusinessScoreExponent,
+		SlackBand: defaultBusinessScoreSlackBand,
+	}
+
+	raw, ok := spd.GetAnnotations()[businessScoreOptionsAnnotation]
+	if !ok {
+		return options, nil
+	}
+
+	var override businessScoreOptionsOverride
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&override); err != nil {
+		return options, fmt.Errorf("invalid %s annotation: %v", businessScoreOptionsAnnotation, err)
+	}
+
+	if override.Weights != nil {
+		options.Weights = override.Weights
+	}
+	if override.Exponent != nil {
+		options.Exponent = *override.Exponent
+	}
+	if override.SlackBand != nil {
+		options.SlackBand = *override.SlackBand
+	}
+	if override.Permissive != nil {
+		options.Permissive = *override.Permissive
+	}
+
+	return options, nil
+}